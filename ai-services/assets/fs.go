@@ -10,3 +10,6 @@ var BootstrapFS embed.FS
 
 //go:embed catalog architectures services
 var CatalogFS embed.FS
+
+//go:embed models
+var ModelFS embed.FS
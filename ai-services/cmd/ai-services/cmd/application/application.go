@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/application/bundle"
 	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/application/image"
 	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/application/model"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
@@ -42,14 +43,20 @@ var ApplicationCmd = &cobra.Command{
 func init() {
 	ApplicationCmd.AddCommand(templatesCmd)
 	ApplicationCmd.AddCommand(createCmd)
+	ApplicationCmd.AddCommand(upgradeCmd)
 	ApplicationCmd.AddCommand(psCmd)
 	ApplicationCmd.AddCommand(deleteCmd)
 	ApplicationCmd.AddCommand(image.ImageCmd)
 	ApplicationCmd.AddCommand(stopCmd)
 	ApplicationCmd.AddCommand(startCmd)
 	ApplicationCmd.AddCommand(infoCmd)
+	ApplicationCmd.AddCommand(getValuesCmd)
+	ApplicationCmd.AddCommand(diffCmd)
+	ApplicationCmd.AddCommand(backupCmd)
+	ApplicationCmd.AddCommand(restoreCmd)
 	ApplicationCmd.AddCommand(logsCmd)
 	ApplicationCmd.AddCommand(model.ModelCmd)
+	ApplicationCmd.AddCommand(bundle.BundleCmd)
 
 	// Add runtime flag as required
 	ApplicationCmd.PersistentFlags().StringVar(&runtimeType, "runtime", "", fmt.Sprintf("runtime to use (options: %s, %s) (required)", types.RuntimeTypePodman, types.RuntimeTypeOpenShift))
@@ -57,6 +64,16 @@ func init() {
 
 	ApplicationCmd.PersistentFlags().StringVar(&vars.ToolImage, "tool-image", vars.ToolImage, "Tool image to use for downloading the model(only for the development purpose)")
 	ApplicationCmd.PersistentFlags().BoolVar(&hiddenTemplates, "hidden", false, "Show hidden templates")
+	ApplicationCmd.PersistentFlags().IntVar(&vars.MaxParallelDownloads, "max-parallel-downloads", vars.MaxParallelDownloads,
+		"Number of images pulled, or models downloaded, at once")
+	ApplicationCmd.PersistentFlags().StringVar(&vars.RegistriesConfig, "registries-config", vars.RegistriesConfig,
+		"Registry configuration: the mirrors the image references of the templates are rewritten to "+
+			"(Eg:- mirrors: [{prefix: icr.io, location: quay.example.com/icr}]), and the authFile, retries and "+
+			"per-registry username, password and tlsVerify the images are pulled with")
+	ApplicationCmd.PersistentFlags().StringVar(&vars.ModelSourcesConfig, "model-sources-config", vars.ModelSourcesConfig,
+		"Model sources configuration: the mirrors the models are downloaded from instead of the Hugging Face hub "+
+			"(Eg:- mirrors: [{prefix: ibm-granite, location: s3://models/approved}], locations hf://, https:// for a "+
+			"Hugging Face compatible mirror, file:// or s3://), and the s3 endpoint, region and credentials")
 	_ = ApplicationCmd.PersistentFlags().MarkHidden("tool-image")
 	_ = ApplicationCmd.PersistentFlags().MarkHidden("hidden")
 }
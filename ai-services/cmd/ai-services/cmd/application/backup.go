@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application"
+	appTypes "github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	appFlags "github.com/project-ai-services/ai-services/internal/pkg/cli/constants/application"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var (
+	backupOutput string
+	backupLive   bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup [name]",
+	Short: "Backs up the data of an application",
+	Long: `Writes the data directory of an application (OpenSearch data, digitized documents, cache), along with the
+values and the template it is deployed with, to a gzipped tar archive. The archive is restored with
+'ai-services application restore', for example on a rebuilt host.
+
+The running pods of the application are stopped while the data is copied, so that the copy is consistent,
+and started again once it is done. With --live the data is copied while the application runs.
+
+The archive holds the secrets of the application in clear, it is written with 0600 permissions.
+
+Arguments
+  [name]: Application name (required)
+
+Note: Supported for podman runtime only.
+`,
+	Example: `  ai-services application backup rag-dev -o rag-dev.tar.gz`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.CheckPodmanPlatformSupport(vars.RuntimeFactory.GetRuntimeType()); err != nil {
+			return err
+		}
+
+		return utils.VerifyAppName(args[0])
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		applicationName := args[0]
+
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		factory := application.NewFactory(vars.RuntimeFactory.GetRuntimeType())
+		app, err := factory.Create(applicationName)
+		if err != nil {
+			return fmt.Errorf("failed to create application instance: %w", err)
+		}
+
+		opts := appTypes.BackupOptions{
+			Name:    applicationName,
+			Output:  backupOutput,
+			Live:    backupLive,
+			AutoYes: autoYes,
+		}
+
+		return app.Backup(context.Background(), opts)
+	},
+}
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupOutput, appFlags.Backup.Output, "o", "", "Path of the backup archive to write (Required)")
+	_ = backupCmd.MarkFlagRequired(appFlags.Backup.Output)
+	backupCmd.Flags().BoolVar(&backupLive, appFlags.Backup.Live, false,
+		"Copy the data while the application runs, instead of stopping its pods during the copy.\n"+
+			"The copy may be inconsistent if the application writes its data meanwhile\n")
+	backupCmd.Flags().BoolVarP(&autoYes, appFlags.Backup.AutoYes, "y", false, "Automatically accept all confirmation prompts (default=false)")
+}
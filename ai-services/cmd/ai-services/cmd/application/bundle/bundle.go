@@ -0,0 +1,29 @@
+package bundle
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var BundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage air-gapped bundles of application images and models",
+	Long: `Bundles hold the container images and the models of an application template in a single file, to deploy
+the application on hosts without internet access.
+
+Create the bundle on a host with internet access, copy it to the target host and load it there. The application
+is then created without pulling images nor downloading models:
+
+  ai-services application bundle create -t rag -o rag-bundle.tar
+  ai-services application bundle load rag-bundle.tar
+  ai-services application create rag-dev -t rag --image-pull-policy Never --skip-model-download
+`,
+	Args: cobra.MaximumNArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	BundleCmd.AddCommand(createCmd)
+	BundleCmd.AddCommand(loadCmd)
+}
@@ -0,0 +1,166 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/bundle"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelsource"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var (
+	templateName string
+	output       string
+	rawArgParams []string
+	valuesFiles  []string
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Creates a bundle of the images and the models of an application template",
+	Long: `Exports every container image of an application template, the tool image included, and every model
+it requires into a single bundle, along with a manifest holding the SHA256 checksum of every file.
+
+The images missing locally are pulled and the models missing from the model directory are downloaded first.
+The images and the models bundled are the ones of the values overridden with --params and --values, such as
+the models set with --params models.<role>=<model>, which the application must then be created with.
+
+Note: Supported for podman runtime only.
+`,
+	Example: `  ai-services application bundle create -t rag -o rag-bundle.tar
+
+  # Bundle another model than the default one of the template
+  ai-services application bundle create -t rag --params models.instruct=ibm-granite/granite-3.1-8b-instruct`,
+	Args: cobra.MaximumNArgs(0),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return utils.CheckPodmanPlatformSupport(vars.RuntimeFactory.GetRuntimeType())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		argParams, err := utils.ParseKeyValues(rawArgParams)
+		if err != nil {
+			return fmt.Errorf("invalid format of --params: %w", err)
+		}
+
+		for _, vf := range valuesFiles {
+			if !utils.FileExists(vf) {
+				return fmt.Errorf("values file '%s' does not exist", vf)
+			}
+		}
+
+		if output == "" {
+			output = templateName + "-bundle.tar"
+		}
+
+		return create(templateName, output, valuesFiles, argParams)
+	},
+}
+
+func init() {
+	createCmd.Flags().StringVarP(&templateName, "template", "t", "", "Application template name (Required)")
+	_ = createCmd.MarkFlagRequired("template")
+	createCmd.Flags().StringVarP(&output, "output", "o", "", "Path of the bundle to write (default \"<template>-bundle.tar\")")
+	createCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory holding the model files")
+	createCmd.Flags().StringSliceVar(&rawArgParams, "params", []string{},
+		"Inline parameters overriding the values of the application template (Eg:- --params models.instruct=ibm-granite/granite-3.1-8b-instruct)")
+	createCmd.Flags().StringArrayVarP(&valuesFiles, "values", "f", []string{},
+		"Values files overriding the values of the application template, applied in order")
+}
+
+func create(template, bundlePath string, valuesFiles []string, argParams map[string]string) error {
+	runtimeClient, err := podman.NewPodmanClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to podman: %w", err)
+	}
+
+	// the images are bundled by tag, the digests of the lock file do not survive the export to an archive
+	img := &image.Images{Runtime: runtimeClient, AppTemplate: template, ValuesFiles: valuesFiles, ArgParams: argParams, IgnoreLock: true}
+	images, err := img.ListImages()
+	if err != nil {
+		return fmt.Errorf("error listing images: %w", err)
+	}
+
+	if err := img.Run(image.PullIfNotPresent); err != nil {
+		return err
+	}
+
+	models, err := helpers.ListModels(template, "", valuesFiles, argParams)
+	if err != nil {
+		return err
+	}
+
+	if err := downloadMissingModels(models); err != nil {
+		return err
+	}
+
+	w, err := bundle.NewWriter(bundlePath, bundle.Manifest{Template: template, CLIVersion: vars.CLIVersion, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	for i, ref := range images {
+		logger.Infof("Adding image %s to the bundle...\n", ref)
+		if err := w.AddImage(runtimeClient, ref, i); err != nil {
+			return abort(w, err)
+		}
+	}
+
+	for _, spec := range models {
+		model := modelsource.Name(spec)
+		logger.Infof("Adding model %s to the bundle...\n", model)
+		if err := w.AddModel(vars.ModelDirectory, model); err != nil {
+			return abort(w, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	logger.Infof("Bundle of template '%s' with %d images and %d models written to '%s'\n", template, len(images), len(models), bundlePath)
+
+	return nil
+}
+
+// downloadMissingModels downloads the models missing from the model directory, or downloaded at another revision than
+// the one they are pinned to, with the tool image pulled by tag.
+func downloadMissingModels(models []string) error {
+	missing := slices.DeleteFunc(slices.Clone(models), func(spec string) bool {
+		if _, err := os.Stat(filepath.Join(vars.ModelDirectory, modelsource.Name(spec))); err != nil {
+			return false
+		}
+
+		return helpers.CheckModelRevision(spec, vars.ModelDirectory) == nil
+	})
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if err := helpers.DownloadModels("", missing, vars.ModelDirectory); err != nil {
+		return fmt.Errorf("failed to download model: %w", err)
+	}
+
+	return nil
+}
+
+// abort removes the partially written bundle, and returns the error which interrupted it.
+func abort(w *bundle.Writer, err error) error {
+	if abortErr := w.Abort(); abortErr != nil {
+		logger.Warningf("%v\n", abortErr)
+	}
+
+	return err
+}
@@ -0,0 +1,64 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/bundle"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load [file]",
+	Short: "Loads the images and the models of a bundle",
+	Long: `Loads a bundle written by 'ai-services application bundle create': its images into the local storage of podman,
+and its models into the model directory, replacing the models of the same name.
+
+The checksum of every file of the bundle is verified before anything is loaded.
+
+Arguments
+  [file]: Path of the bundle (required)
+
+Note: Supported for podman runtime only.
+`,
+	Example: `  ai-services application bundle load rag-bundle.tar`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.CheckPodmanPlatformSupport(vars.RuntimeFactory.GetRuntimeType()); err != nil {
+			return err
+		}
+
+		if !utils.FileExists(args[0]) {
+			return fmt.Errorf("file '%s' does not exist", args[0])
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		runtimeClient, err := podman.NewPodmanClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect to podman: %w", err)
+		}
+
+		manifest, err := bundle.Load(runtimeClient, args[0], vars.ModelDirectory)
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("Loaded %d images and %d models of template '%s', create the application with "+
+			"'--image-pull-policy Never --skip-model-download'\n", len(manifest.Images), len(manifest.Models), manifest.Template)
+
+		return nil
+	},
+}
+
+func init() {
+	loadCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory to load the model files into")
+}
@@ -22,12 +22,16 @@ import (
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 )
 
+const outputFormatYAML = "yaml"
+
 // Variables for flags placeholder.
 var (
 	// common flags.
 	templateName string
 	rawArgParams []string
 	argParams    map[string]string
+	dryRun       bool
+	dryRunOutput string
 
 	// podman flags.
 	skipModelDownload     bool
@@ -35,6 +39,13 @@ var (
 	skipChecks            []string
 	valuesFiles           []string
 	rawArgImagePullPolicy string
+	maxParallelism        int
+	keepOnFailure         bool
+	resume                bool
+	force                 bool
+	verifyImages          bool
+	signaturePolicy       string
+	signatureKey          string
 
 	// openshift flags.
 	timeout time.Duration
@@ -71,8 +82,11 @@ var createCmd = &cobra.Command{
 		// Once precheck passes, silence usage for any *later* internal errors.
 		cmd.SilenceUsage = true
 
-		if err := doBootstrapValidate(); err != nil {
-			return err
+		// a dry run has no side effect on the host, hence it can be run outside of a configured host
+		if !dryRun {
+			if err := doBootstrapValidate(); err != nil {
+				return err
+			}
 		}
 
 		// Create application instance using factory
@@ -90,7 +104,14 @@ var createCmd = &cobra.Command{
 			ArgParams:         argParams,
 			ValuesFiles:       valuesFiles,
 			ImagePullPolicy:   image.ImagePullPolicy(rawArgImagePullPolicy),
+			MaxParallelism:    maxParallelism,
+			KeepOnFailure:     keepOnFailure,
+			Resume:            resume,
+			Force:             force,
+			VerifyImages:      verifyImagesOptions(),
 			Timeout:           timeout,
+			DryRun:            dryRun,
+			Output:            dryRunOutput,
 		}
 
 		return app.Create(ctx, opts)
@@ -134,7 +155,9 @@ func initCreateCommonFlags() {
 			"Format:\n"+
 			"- Comma-separated key=value pairs\n"+
 			"- Example: --params key1=value1,key2=value2\n\n"+
-			"- Use \"ai-services application templates\" to view the list of supported parameters\n\n"+
+			"- Use \"ai-services application templates\" to view the list of supported parameters\n"+
+			"- The models are set with models.<role>=<model> on the podman runtime only, the models of the\n"+
+			"  OpenShift templates cannot be overridden, see \"ai-services application model catalog\"\n\n"+
 			"Precedence:\n"+
 			"- When both --values and --params are provided, --params overrides --values\n",
 	)
@@ -148,6 +171,25 @@ func initCreateCommonFlags() {
 			"Usage:\n"+
 			"- Can be provided multiple times; files are applied in order and later files override earlier ones\n",
 	)
+
+	createCmd.Flags().BoolVar(
+		&dryRun,
+		appFlags.Create.DryRun,
+		false,
+		"Render the application without deploying it\n\n"+
+			"Nothing is pulled, downloaded or deployed, and the host is not validated\n"+
+			" - podman: the pod specs are rendered with a simulated Spyre card allocation and printed as the kube YAML\n"+
+			"   that would be deployed, while the images, models and deployment layers are logged\n"+
+			" - openshift: the chart is rendered like 'helm template'\n",
+	)
+
+	createCmd.Flags().StringVarP(
+		&dryRunOutput,
+		appFlags.Create.Output,
+		"o",
+		outputFormatYAML,
+		"Output format of the rendered application with --dry-run. Supported values: yaml\n",
+	)
 }
 
 func initCreatePodmanFlags() {
@@ -172,11 +214,55 @@ func initCreatePodmanFlags() {
 			"Recommended for air-gapped networks\n\n"+
 			"Warning:\n"+
 			"- If set to true and models are missing → command will fail\n"+
-			"- If left false in air-gapped environments → download attempt will fail\n"+
+			"- If left false in air-gapped environments → download attempt will fail\n\n"+
+			"On openshift runtime, the models are downloaded to the 'models' PVC by a Job before the install,\n"+
+			"the flag leaves them for KServe to download when the InferenceServices start.\n",
+	)
+
+	createCmd.Flags().IntVar(
+		&maxParallelism,
+		appFlags.Create.MaxParallelism,
+		0,
+		"Maximum number of pods deployed in parallel\n\n"+
+			"Pods are deployed as soon as the pods they depend on are ready\n"+
+			"Defaults to the value set in the application template, or no limit if unset\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+
+	createCmd.Flags().BoolVar(
+		&keepOnFailure,
+		appFlags.Create.KeepOnFailure,
+		false,
+		"Keep the pods created by this run if the deployment fails\n\n"+
+			"By default, the pods created by this run are removed on failure and their Spyre cards are freed\n"+
+			"Use this to debug the failed pods, and remove them later with 'ai-services application delete'\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+
+	createCmd.Flags().BoolVar(
+		&resume,
+		appFlags.Create.Resume,
+		false,
+		"Resume an interrupted or failed create from its last completed step\n\n"+
+			"The progress of create is journaled under /var/lib/ai-services/applications/<name>\n"+
+			"Steps already completed (image pull, model download) are skipped, healthy pods are kept\n"+
+			"and pods which are half-started or unhealthy are removed and deployed again\n"+
+			"The values and params of the interrupted create are reused, the ones given applying on top of them\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+
+	createCmd.Flags().BoolVar(
+		&force,
+		appFlags.Create.Force,
+		false,
+		"Deploy even if the pre-flight checks on host capacity fail\n\n"+
+			"Before deploying, the memory and CPU requested by the pods, the free space for the models\n"+
+			"and the host ports are checked against the host. Failed checks are reported as warnings instead\n\n"+
 			"Note: Supported for podman runtime only.\n",
 	)
 
 	initializeImagePullPolicyFlag()
+	initializeVerifyImagesFlags()
 
 	// deprecated flags
 	deprecatedPodmanFlags()
@@ -188,6 +274,7 @@ func initCreateOpenShiftFlags() {
 		appFlags.Create.Timeout,
 		0, // default
 		"Timeout for the operation (e.g. 10s, 2m, 1h).\n"+
+			"Bounds the model download and the install, each on their own.\n"+
 			"Note: Supported for openshift runtime only.\n",
 	)
 }
@@ -208,6 +295,42 @@ func initializeImagePullPolicyFlag() {
 	)
 }
 
+func initializeVerifyImagesFlags() {
+	createCmd.Flags().BoolVar(
+		&verifyImages,
+		appFlags.Create.VerifyImages,
+		false,
+		"Verify the container images before deploying the application\n\n"+
+			"Fails if any image is not pinned to a digest, by the template or by its lock file\n"+
+			"(see 'ai-services application image lock'), is not present locally with that digest,\n"+
+			"or is not signed as required by --signature-key or --signature-policy\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+	createCmd.Flags().StringVar(
+		&signaturePolicy,
+		appFlags.Create.SignaturePolicy,
+		"/etc/containers/policy.json",
+		"Containers policy the signatures of the images are verified against with --verify-images\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+	createCmd.Flags().StringVar(
+		&signatureKey,
+		appFlags.Create.SignatureKey,
+		"",
+		"Sigstore (cosign) public key the images must be signed with, used instead of --signature-policy with --verify-images\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+}
+
+// verifyImagesOptions returns how the images are verified, nil unless --verify-images is set.
+func verifyImagesOptions() *image.VerifyOptions {
+	if !verifyImages {
+		return nil
+	}
+
+	return &image.VerifyOptions{PolicyPath: signaturePolicy, KeyPath: signatureKey}
+}
+
 func deprecatedPodmanFlags() {
 	if err := createCmd.Flags().MarkDeprecated(appFlags.Create.SkipImageDownload, "use --image-pull-policy instead"); err != nil {
 		panic(fmt.Sprintf("Failed to mark '%s' flag deprecated. Err: %v", appFlags.Create.SkipImageDownload, err))
@@ -225,13 +348,22 @@ func buildFlagValidator() *flagvalidator.FlagValidator {
 		AddCommonFlag(appFlags.Create.SkipValidation, validateSkipChecksFlag).
 		AddCommonFlag(appFlags.Create.Template, validateTemplateFlag).
 		AddCommonFlag(appFlags.Create.Params, validateParamsFlag).
-		AddCommonFlag(appFlags.Create.Values, validateValuesFlag)
+		AddCommonFlag(appFlags.Create.Values, validateValuesFlag).
+		AddCommonFlag(appFlags.Create.DryRun, nil).
+		AddCommonFlag(appFlags.Create.SkipModelDownload, nil).
+		AddCommonFlag(appFlags.Create.Output, validateOutputFlag)
 
 	// Register Podman-specific flags
 	builder.
 		AddPodmanFlag(appFlags.Create.SkipImageDownload, nil).
-		AddPodmanFlag(appFlags.Create.SkipModelDownload, nil).
-		AddPodmanFlag(appFlags.Create.ImagePullPolicy, validateImagePullPolicyFlag)
+		AddPodmanFlag(appFlags.Create.ImagePullPolicy, validateImagePullPolicyFlag).
+		AddPodmanFlag(appFlags.Create.MaxParallelism, validateMaxParallelismFlag).
+		AddPodmanFlag(appFlags.Create.KeepOnFailure, nil).
+		AddPodmanFlag(appFlags.Create.Resume, nil).
+		AddPodmanFlag(appFlags.Create.Force, nil).
+		AddPodmanFlag(appFlags.Create.VerifyImages, nil).
+		AddPodmanFlag(appFlags.Create.SignaturePolicy, nil).
+		AddPodmanFlag(appFlags.Create.SignatureKey, nil)
 
 	// Register OpenShift-specific flags
 	builder.
@@ -250,6 +382,19 @@ func validateTemplateFlag(cmd *cobra.Command) error {
 	return nil
 }
 
+// validateOutputFlag validates the output flag, which is only meaningful for a dry run.
+func validateOutputFlag(cmd *cobra.Command) error {
+	if dryRunOutput != outputFormatYAML {
+		return fmt.Errorf("invalid value %q: must be %q", dryRunOutput, outputFormatYAML)
+	}
+
+	if !dryRun {
+		return fmt.Errorf("--%s is only supported with --%s", appFlags.Create.Output, appFlags.Create.DryRun)
+	}
+
+	return nil
+}
+
 // validateParamsFlag validates the params flag.
 func validateParamsFlag(cmd *cobra.Command) error {
 	if len(rawArgParams) == 0 {
@@ -302,6 +447,15 @@ func validateImagePullPolicyFlag(cmd *cobra.Command) error {
 	return nil
 }
 
+// validateMaxParallelismFlag validates the max-parallelism flag.
+func validateMaxParallelismFlag(cmd *cobra.Command) error {
+	if maxParallelism < 0 {
+		return fmt.Errorf("invalid value %d: must not be negative", maxParallelism)
+	}
+
+	return nil
+}
+
 // validateSkipChecksFlag validates the skipChecks flag for the current runtime.
 func validateSkipChecksFlag(cmd *cobra.Command) error {
 	if len(skipChecks) == 0 {
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application"
+	appTypes "github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [name]",
+	Short: "Detects the drift of a deployed application",
+	Long: `Compares what a deployed application runs with what it is expected to run, and reports the drift.
+
+Podman: the pod templates are rendered with the stored values of the application and the templates of this
+release, and compared with the running pods and containers: images, env, ports, annotations and resource limits.
+This catches the containers edited by hand, and the images left behind by an upgrade of the CLI.
+
+OpenShift: the objects of the manifest of the Helm release are compared with the objects live in the cluster.
+
+Only the fields set by the application are compared. Secrets are redacted.
+
+Arguments
+  [name]: Application name (required)
+`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return utils.VerifyAppName(args[0])
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		applicationName := args[0]
+
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		factory := application.NewFactory(vars.RuntimeFactory.GetRuntimeType())
+		app, err := factory.Create(applicationName)
+		if err != nil {
+			return fmt.Errorf("failed to create application instance: %w", err)
+		}
+
+		drifts, err := app.Diff(context.Background(), appTypes.DiffOptions{Name: applicationName})
+		if err != nil {
+			return err
+		}
+
+		if len(drifts) == 0 {
+			logger.Infof("No drift detected, application '%s' runs what it is expected to run\n", applicationName)
+
+			return nil
+		}
+
+		printDrifts(drifts)
+		logger.Infof("%d drifted fields detected for application '%s'\n", len(drifts), applicationName)
+
+		return nil
+	},
+}
+
+func printDrifts(drifts []appTypes.Drift) {
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("RESOURCE", "CONTAINER", "FIELD", "EXPECTED", "ACTUAL")
+	for _, d := range drifts {
+		printer.AppendRow(d.Resource, d.Container, d.Field, d.Expected, d.Actual)
+	}
+}
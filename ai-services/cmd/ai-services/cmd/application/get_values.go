@@ -0,0 +1,76 @@
+package application
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application"
+	appTypes "github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	appFlags "github.com/project-ai-services/ai-services/internal/pkg/cli/constants/application"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var (
+	getValuesAll         bool
+	getValuesShowSecrets bool
+)
+
+var getValuesCmd = &cobra.Command{
+	Use:   "get-values [name]",
+	Short: "Prints the values of a deployed application",
+	Long: `Prints the values a deployed application is deployed with, as YAML.
+
+By default only the values provided with --values and --params are printed. The output can be
+passed to 'ai-services application create --values' to reproduce the deployment, for example on another host.
+
+Secrets are redacted, unless --show-secrets is set.
+
+Arguments
+  [name]: Application name (required)
+`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return utils.VerifyAppName(args[0])
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		applicationName := args[0]
+
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		factory := application.NewFactory(vars.RuntimeFactory.GetRuntimeType())
+		app, err := factory.Create(applicationName)
+		if err != nil {
+			return fmt.Errorf("failed to create application instance: %w", err)
+		}
+
+		values, err := app.GetValues(appTypes.GetValuesOptions{
+			Name:        applicationName,
+			All:         getValuesAll,
+			ShowSecrets: getValuesShowSecrets,
+		})
+		if err != nil {
+			return err
+		}
+
+		data, err := yaml.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("failed to marshal values: %w", err)
+		}
+
+		if _, err := os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("failed to print values: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	getValuesCmd.Flags().BoolVarP(&getValuesAll, appFlags.GetValues.All, "a", false, "Print all the values, merged with the template defaults")
+	getValuesCmd.Flags().BoolVar(&getValuesShowSecrets, appFlags.GetValues.ShowSecrets, false, "Print the secrets in clear instead of redacting them")
+}
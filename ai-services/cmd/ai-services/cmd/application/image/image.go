@@ -19,6 +19,7 @@ var ImageCmd = &cobra.Command{
 func init() {
 	ImageCmd.AddCommand(listCmd)
 	ImageCmd.AddCommand(pullCmd)
+	ImageCmd.AddCommand(lockCmd)
 	ImageCmd.PersistentFlags().StringVarP(&templateName, "template", "t", "", "Application template name (Required)")
 	_ = ImageCmd.MarkPersistentFlagRequired("template")
 }
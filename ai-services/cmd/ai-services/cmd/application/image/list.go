@@ -2,10 +2,12 @@ package image
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/project-ai-services/ai-services/internal/pkg/image"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 	"github.com/spf13/cobra"
 )
@@ -34,14 +36,29 @@ func list(templateName string) error {
 	img := &image.Images{
 		AppTemplate: templateName,
 	}
-	images, err := img.ListImages()
+	refs, err := img.ListImageRefs()
 	if err != nil {
 		return fmt.Errorf("error listing images: %w", err)
 	}
 
-	logger.Infof("Container images for application template '%s' are:\n", templateName)
-	for _, image := range images {
-		logger.Infoln("- " + image)
+	if !slices.ContainsFunc(refs, func(ref image.Ref) bool { return ref.Original != ref.Rewritten || ref.Digest != "" }) {
+		logger.Infof("Container images for application template '%s' are:\n", templateName)
+		for _, ref := range refs {
+			logger.Infoln("- " + ref.Original)
+		}
+
+		return nil
+	}
+
+	logger.Infof("Container images for application template '%s', rewritten to the registry mirrors of %s and pinned to their digests, are:\n",
+		templateName, vars.RegistriesConfig)
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("IMAGE", "REWRITTEN", "DIGEST")
+	for _, ref := range refs {
+		printer.AppendRow(ref.Original, ref.Rewritten, ref.Digest)
 	}
 
 	return nil
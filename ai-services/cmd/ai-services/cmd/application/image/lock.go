@@ -0,0 +1,64 @@
+package image
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Pins the container images of a given application template to their digests",
+	Long: `Resolves the references of the container images of the application template to their digests in the
+registries, and writes them to the lock file of the template. The images are then pulled and run by digest,
+and verified against the lock file with 'application create --verify-images', until the lock file is generated again.`,
+	Args: cobra.MaximumNArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		return lock(cmd.Context(), templateName)
+	},
+}
+
+func lock(ctx context.Context, template string) error {
+	if vars.RuntimeFactory.GetRuntimeType() == types.RuntimeTypeOpenShift {
+		// Since we do not have templates in OpenShift marking it as unsupported for now
+		logger.Warningln("Not supported for openshift runtime")
+
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	img := &image.Images{AppTemplate: template}
+	lockFile, err := img.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to lock the images of template %s: %w", template, err)
+	}
+
+	path := image.LockPath(template)
+	if err := lockFile.Write(path); err != nil {
+		return err
+	}
+
+	logger.Infof("Container images of application template '%s' pinned in %s:\n", template, path)
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("IMAGE", "DIGEST")
+	for _, locked := range lockFile.Images {
+		printer.AppendRow(locked.Image, locked.Digest)
+	}
+
+	return nil
+}
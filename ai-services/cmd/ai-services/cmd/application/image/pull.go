@@ -32,25 +32,17 @@ func pull(template string) error {
 		return nil
 	}
 
-	img := &image.Images{
-		AppTemplate: template,
-	}
-	images, err := img.ListImages()
-	if err != nil {
-		return fmt.Errorf("error listing images: %w", err)
-	}
-
 	logger.Infof("Downloading the images for the application... ")
 	runtimeClient, err := podman.NewPodmanClient()
 	if err != nil {
 		return fmt.Errorf("failed to connect to podman: %w", err)
 	}
 
-	for _, image := range images {
-		if err := runtimeClient.PullImage(image); err != nil {
-			return fmt.Errorf("failed to pull the image: %w", err)
-		}
+	// the images are pulled with the credentials and TLS settings of the registries config
+	img := &image.Images{
+		Runtime:     runtimeClient,
+		AppTemplate: template,
 	}
 
-	return nil
+	return img.Run(image.PullAlways)
 }
@@ -10,6 +10,8 @@ import (
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 )
 
+var infoShowValues bool
+
 var infoCmd = &cobra.Command{
 	Use:   "info [name]",
 	Short: "Application info",
@@ -35,9 +37,14 @@ var infoCmd = &cobra.Command{
 		}
 
 		opts := appTypes.InfoOptions{
-			Name: applicationName,
+			Name:       applicationName,
+			ShowValues: infoShowValues,
 		}
 
 		return app.Info(opts)
 	},
 }
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoShowValues, "values", false, "Display the values the application is deployed with, merged with the template defaults (secrets are redacted)")
+}
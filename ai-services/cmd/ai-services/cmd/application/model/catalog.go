@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcatalog"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "List the models the application templates can serve",
+	Long: `Lists the models of the model catalog, which the models set with --params models.<role>=<model>
+are checked against: the roles they can serve, the number of Spyre cards they are served on,
+and whether they are supported on CPU.
+
+Note: The models are overridden on the podman runtime only, the OpenShift templates serve their default models.`,
+	Args: cobra.MaximumNArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		return listCatalog()
+	},
+}
+
+func listCatalog() error {
+	catalog, err := modelcatalog.Load()
+	if err != nil {
+		return err
+	}
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("MODEL", "ROLES", "SPYRE CARDS", "CPU")
+	for _, entry := range catalog.Models {
+		spyreCards := "-"
+		if entry.Supports(modelcatalog.AcceleratorSpyre) {
+			spyreCards = strconv.Itoa(entry.Spyre.Cards)
+		}
+		cpu := "no"
+		if entry.CPU {
+			cpu = "yes"
+		}
+		printer.AppendRow(entry.Name, strings.Join(entry.Roles, ","), spyreCards, cpu)
+	}
+
+	return nil
+}
@@ -1,20 +1,32 @@
 package model
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/project-ai-services/ai-services/internal/pkg/application/openshift"
+	appTypes "github.com/project-ai-services/ai-services/internal/pkg/application/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 	"github.com/spf13/cobra"
 )
 
+var appName string
+
 var downloadCmd = &cobra.Command{
 	Use:   "download",
 	Short: "Download models for a given application template",
-	Long:  ``,
-	Args:  cobra.MaximumNArgs(0),
+	Long: `Downloads the models of an application template.
+
+Podman: the models are downloaded to the model directory of the host.
+
+OpenShift: the models are downloaded to the 'models' PVC in the namespace of the application given with --app,
+created if missing, each by a Job running the tool image, as 'ai-services application create' does.
+`,
+	Args: cobra.MaximumNArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Once precheck passes, silence usage for any *later* internal errors.
 		cmd.SilenceUsage = true
@@ -30,14 +42,13 @@ func init() {
 	downloadCmd.Flags().StringVar(&vars.ToolImage, "tool-image", vars.ToolImage, "Tool container image used for downloading the model (for development purposes only)")
 	_ = downloadCmd.Flags().MarkHidden("tool-image")
 	downloadCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory to download the model files")
+	downloadCmd.Flags().StringVar(&appName, "app", "", "Application the models are downloaded for, whose namespace holds the models PVC (Required for openshift runtime)")
+	addParamsFlag(downloadCmd)
 }
 
 func download(cmd *cobra.Command) error {
 	if vars.RuntimeFactory.GetRuntimeType() == types.RuntimeTypeOpenShift {
-		// Since we do not have tmpl files in OpenShift marking it as unsupported for now
-		logger.Warningln("Not supported for openshift runtime")
-
-		return nil
+		return downloadToPVC()
 	}
 
 	models, err := models(templateName)
@@ -45,12 +56,35 @@ func download(cmd *cobra.Command) error {
 		return err
 	}
 	logger.Infoln("Downloaded Models in application template" + templateName + ":")
-	for _, model := range models {
-		err := helpers.DownloadModel(model, vars.ModelDirectory)
-		if err != nil {
-			return fmt.Errorf("failed to download model: %w", err)
-		}
+	if err := helpers.DownloadModels(templateName, models, vars.ModelDirectory); err != nil {
+		return fmt.Errorf("failed to download model: %w", err)
 	}
 
 	return nil
 }
+
+// downloadToPVC downloads the models of the application template to the models PVC of the application.
+func downloadToPVC() error {
+	if appName == "" {
+		return fmt.Errorf("--app is required for openshift runtime, the models are downloaded to the PVC of the application")
+	}
+	if err := utils.VerifyAppName(appName); err != nil {
+		return err
+	}
+
+	argParams, err := utils.ParseKeyValues(rawArgParams)
+	if err != nil {
+		return fmt.Errorf("invalid format of --params: %w", err)
+	}
+
+	runtimeClient, err := vars.RuntimeFactory.Create(appName)
+	if err != nil {
+		return fmt.Errorf("failed to create runtime client: %w", err)
+	}
+
+	return openshift.NewOpenshiftApplication(runtimeClient).DownloadModels(context.Background(), appTypes.CreateOptions{
+		Name:         appName,
+		TemplateName: templateName,
+		ArgParams:    argParams,
+	})
+}
@@ -2,32 +2,53 @@ package model
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcache"
+	"github.com/project-ai-services/ai-services/internal/pkg/progress"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 	"github.com/spf13/cobra"
 )
 
-var templateName string
+var (
+	templateName string
+	local        bool
+)
 
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List models for a given application template",
-	Long:  ``,
-	Args:  cobra.MaximumNArgs(0),
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List models for a given application template, or the models downloaded locally",
+	Long: `Lists the models of the application template given with --template.
+
+With --local, lists the models downloaded to the model directory instead, along with the revision
+they were pinned to, their size, the last time they were used and the applications whose pods reference them.`,
+	Args: cobra.MaximumNArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Once precheck passes, silence usage for any *later* internal errors.
 		cmd.SilenceUsage = true
 		hiddenTemplates, _ = cmd.Flags().GetBool("hidden")
 
+		if local {
+			return listLocal()
+		}
+
 		return list(cmd)
 	},
 }
 
 func init() {
-	listCmd.Flags().StringVarP(&templateName, "template", "t", "", "Application template name (Required)")
-	_ = listCmd.MarkFlagRequired("template")
+	listCmd.Flags().StringVarP(&templateName, "template", "t", "", "Application template name (Required unless --local is set)")
+	listCmd.Flags().BoolVar(&local, "local", false, "List the models downloaded to the model directory")
+	listCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory the models are downloaded to")
+	listCmd.MarkFlagsOneRequired("template", "local")
+	listCmd.MarkFlagsMutuallyExclusive("template", "local")
+	addParamsFlag(listCmd)
 }
 
 func list(cmd *cobra.Command) error {
@@ -49,3 +70,68 @@ func list(cmd *cobra.Command) error {
 
 	return nil
 }
+
+func listLocal() error {
+	if vars.RuntimeFactory.GetRuntimeType() == types.RuntimeTypeOpenShift {
+		logger.Warningln("Not supported for openshift runtime")
+
+		return nil
+	}
+
+	localModels, err := localModels()
+	if err != nil {
+		return err
+	}
+
+	if len(localModels) == 0 {
+		logger.Infof("No models downloaded to %s\n", vars.ModelDirectory)
+
+		return nil
+	}
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("MODEL", "REVISION", "SIZE", "LAST USED", "APPLICATIONS")
+	for _, model := range localModels {
+		apps := strings.Join(model.Apps, ",")
+		if apps == "" {
+			apps = "-"
+		}
+		lastUsed := "-"
+		if !model.LastUsed.IsZero() {
+			lastUsed = model.LastUsed.Local().Format(time.DateTime)
+		}
+		revision := model.Revision
+		if revision == "" {
+			revision = "-"
+		}
+		printer.AppendRow(model.Name, revision, progress.FormatBytes(model.Size), lastUsed, apps)
+	}
+
+	return nil
+}
+
+// localModels returns the models of the model directory along with the applications referencing them.
+func localModels() ([]modelcache.Model, error) {
+	localModels, err := modelcache.List(vars.ModelDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeClient, err := podman.NewPodmanClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to podman: %w", err)
+	}
+
+	refs, err := modelcache.References(runtimeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range localModels {
+		localModels[i].Apps = refs[localModels[i].Name]
+	}
+
+	return localModels, nil
+}
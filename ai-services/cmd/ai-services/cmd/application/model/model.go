@@ -7,6 +7,7 @@ import (
 	"github.com/project-ai-services/ai-services/assets"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -20,13 +21,28 @@ var ModelCmd = &cobra.Command{
 	},
 }
 
-var hiddenTemplates bool
+var (
+	hiddenTemplates bool
+	rawArgParams    []string
+)
+
+// addParamsFlag adds the --params flag overriding the values of the application template, such as its models.
+func addParamsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&rawArgParams, "params", []string{},
+		"Inline parameters overriding the values of the application template (Eg:- --params models.instruct=ibm-granite/granite-3.1-8b-instruct, "+
+			"the models being overridden on the podman runtime only)")
+}
 
 func init() {
 	ModelCmd.AddCommand(listCmd)
 	ModelCmd.AddCommand(downloadCmd)
+	ModelCmd.AddCommand(rmCmd)
+	ModelCmd.AddCommand(pruneCmd)
+	ModelCmd.AddCommand(verifyCmd)
+	ModelCmd.AddCommand(catalogCmd)
 }
 
+// models returns the models of the application template, for the values overridden with --params.
 func models(template string) ([]string, error) {
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 	apps, err := tp.ListApplications(hiddenTemplates)
@@ -38,5 +54,10 @@ func models(template string) ([]string, error) {
 		return nil, fmt.Errorf("application template %s does not exist", template)
 	}
 
-	return helpers.ListModels(template, "")
+	argParams, err := utils.ParseKeyValues(rawArgParams)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format of --params: %w", err)
+	}
+
+	return helpers.ListModels(template, "", nil, argParams)
 }
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcache"
+	"github.com/project-ai-services/ai-services/internal/pkg/progress"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+	"github.com/spf13/cobra"
+)
+
+var pruneAutoYes bool
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the local models no application references",
+	Long: `Removes the models of the model directory which are not referenced by the pods of any deployed
+application, running or not.`,
+	Args: cobra.MaximumNArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		return prune()
+	},
+}
+
+func init() {
+	pruneCmd.Flags().BoolVarP(&pruneAutoYes, "yes", "y", false, "Automatically accept all confirmation prompts (default=false)")
+	pruneCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory the models are downloaded to")
+}
+
+func prune() error {
+	if vars.RuntimeFactory.GetRuntimeType() == types.RuntimeTypeOpenShift {
+		logger.Warningln("Not supported for openshift runtime")
+
+		return nil
+	}
+
+	localModels, err := localModels()
+	if err != nil {
+		return err
+	}
+
+	unused := slices.DeleteFunc(localModels, func(model modelcache.Model) bool { return len(model.Apps) > 0 })
+	if len(unused) == 0 {
+		logger.Infoln("No unused models to remove")
+
+		return nil
+	}
+
+	var size int64
+	logger.Infoln("Models not referenced by any application:")
+	for _, model := range unused {
+		logger.Infof("- %s (%s)\n", model.Name, progress.FormatBytes(model.Size))
+		size += model.Size
+	}
+
+	if !pruneAutoYes {
+		confirmed, err := utils.ConfirmAction(fmt.Sprintf("Remove %d models, freeing %s?", len(unused), progress.FormatBytes(size)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			logger.Infoln("Prune cancelled")
+
+			return nil
+		}
+	}
+
+	for _, model := range unused {
+		if err := modelcache.Remove(vars.ModelDirectory, model.Name); err != nil {
+			return err
+		}
+		logger.Infof("Model removed: %s\n", model.Name)
+	}
+
+	logger.Infof("Freed %s\n", progress.FormatBytes(size))
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcache"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+	"github.com/spf13/cobra"
+)
+
+var forceRemove bool
+
+var rmCmd = &cobra.Command{
+	Use:   "rm [model...]",
+	Short: "Remove models downloaded locally",
+	Long: `Removes the models from the model directory.
+
+Arguments
+  [model...]: Models to remove, as listed by 'ai-services application model list --local' (Eg:- ibm-granite/granite-3.3-8b-instruct)
+
+The models referenced by the pods of an application are kept, unless --force is set.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		return remove(args)
+	},
+}
+
+func init() {
+	rmCmd.Flags().BoolVarP(&forceRemove, "force", "f", false, "Remove the models even if applications reference them")
+	rmCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory the models are downloaded to")
+}
+
+func remove(models []string) error {
+	if vars.RuntimeFactory.GetRuntimeType() == types.RuntimeTypeOpenShift {
+		logger.Warningln("Not supported for openshift runtime")
+
+		return nil
+	}
+
+	runtimeClient, err := podman.NewPodmanClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to podman: %w", err)
+	}
+
+	refs, err := modelcache.References(runtimeClient)
+	if err != nil {
+		return err
+	}
+
+	for _, model := range models {
+		if apps := refs[model]; len(apps) > 0 && !forceRemove {
+			return fmt.Errorf("model %s is referenced by the applications %s, delete them first or use --force",
+				model, strings.Join(apps, ", "))
+		}
+	}
+
+	for _, model := range models {
+		if err := modelcache.Remove(vars.ModelDirectory, model); err != nil {
+			return err
+		}
+		logger.Infof("Model removed: %s\n", model)
+	}
+
+	return nil
+}
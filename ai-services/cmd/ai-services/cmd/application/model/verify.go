@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcache"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+	"github.com/spf13/cobra"
+)
+
+var quickVerify bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [model...]",
+	Short: "Verify the integrity of the models downloaded locally",
+	Long: `Checks the files of the models against the manifests recorded when they were downloaded:
+every file must be present, with its size and its SHA256.
+
+Arguments
+  [model...]: Models to verify (Eg:- ibm-granite/granite-3.3-8b-instruct), all the models downloaded locally if none`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		return verify(args)
+	},
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&quickVerify, "quick", false, "Only check that the files are present with their sizes, without reading them")
+	verifyCmd.Flags().StringVar(&vars.ModelDirectory, "dir", vars.ModelDirectory, "Directory the models are downloaded to")
+}
+
+func verify(models []string) error {
+	if vars.RuntimeFactory.GetRuntimeType() == types.RuntimeTypeOpenShift {
+		logger.Warningln("Not supported for openshift runtime")
+
+		return nil
+	}
+
+	if len(models) == 0 {
+		localModels, err := modelcache.List(vars.ModelDirectory)
+		if err != nil {
+			return err
+		}
+		for _, model := range localModels {
+			models = append(models, model.Name)
+		}
+	}
+
+	mode := modelcache.VerifyChecksum
+	if quickVerify {
+		mode = modelcache.VerifySize
+	}
+
+	var failed int
+	for _, model := range models {
+		err := modelcache.Verify(vars.ModelDirectory, model, mode)
+		switch {
+		case errors.Is(err, modelcache.ErrNoManifest):
+			logger.Warningf("Model %s has no manifest to verify it against, download it again to record one\n", model)
+		case err != nil:
+			logger.Errorf("%v\n", err)
+			failed++
+		default:
+			logger.Infof("Model %s is intact\n", model)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d models failed the verification", failed, len(models))
+	}
+
+	return nil
+}
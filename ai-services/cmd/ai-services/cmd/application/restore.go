@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/backup"
+	appTypes "github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	appFlags "github.com/project-ai-services/ai-services/internal/pkg/cli/constants/application"
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var (
+	restoreSkipModelDownload bool
+	restoreImagePullPolicy   string
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [file]",
+	Short: "Restores an application from a backup",
+	Long: `Restores an application from an archive written by 'ai-services application backup'.
+
+The data directory of the application is restored, and the application is created again with the template
+and the values it was backed up with. The application must not exist on the host, neither its pods nor its data.
+
+Arguments
+  [file]: Path of the backup archive (required)
+
+Note: Supported for podman runtime only.
+`,
+	Example: `  ai-services application restore rag-dev.tar.gz`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.CheckPodmanPlatformSupport(vars.RuntimeFactory.GetRuntimeType()); err != nil {
+			return err
+		}
+
+		if !utils.FileExists(args[0]) {
+			return fmt.Errorf("file '%s' does not exist", args[0])
+		}
+
+		if ok := image.ImagePullPolicy(restoreImagePullPolicy).Valid(); !ok {
+			return fmt.Errorf(
+				"invalid value %q for --%s: must be one of %q, %q, %q", restoreImagePullPolicy,
+				appFlags.Restore.ImagePullPolicy, image.PullAlways, image.PullNever, image.PullIfNotPresent,
+			)
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		contents, err := backup.Read(args[0])
+		if err != nil {
+			return err
+		}
+
+		factory := application.NewFactory(vars.RuntimeFactory.GetRuntimeType())
+		app, err := factory.Create(contents.Manifest.Application)
+		if err != nil {
+			return fmt.Errorf("failed to create application instance: %w", err)
+		}
+
+		opts := appTypes.RestoreOptions{
+			Input:             args[0],
+			SkipModelDownload: restoreSkipModelDownload,
+			ImagePullPolicy:   image.ImagePullPolicy(restoreImagePullPolicy),
+		}
+
+		return app.Restore(context.Background(), opts)
+	},
+}
+
+func init() {
+	restoreCmd.Flags().BoolVar(&restoreSkipModelDownload, appFlags.Restore.SkipModelDownload, false,
+		"Skip model download during application restore\n")
+	restoreCmd.Flags().StringVar(&restoreImagePullPolicy, appFlags.Restore.ImagePullPolicy, string(image.PullIfNotPresent),
+		"Image pull policy for container images required for the restored application. Supported values: Always, Never, IfNotPresent.\n")
+}
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application"
+	appTypes "github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	appFlags "github.com/project-ai-services/ai-services/internal/pkg/cli/constants/application"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/flagvalidator"
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+var (
+	upgradeRawArgParams      []string
+	upgradeArgParams         map[string]string
+	upgradeValuesFiles       []string
+	upgradeDryRun            bool
+	upgradeSkipModelDownload bool
+	upgradeImagePullPolicy   string
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [name]",
+	Short: "Upgrades a deployed application in place",
+	Long: `Upgrades a deployed application with the templates of this release and the given values.
+
+The pod templates are rendered again and compared with the specs the pods are deployed with.
+Only the pods which changed are replaced, one at a time in dependency order, each one having to
+pass its readiness checks before the next one is replaced. A pod failing its readiness checks is
+rolled back to its previous spec and the upgrade stops. Containers keep their Spyre cards.
+
+The values and params the application is deployed with are reused, the given ones apply on top of them.
+
+Arguments
+  [name]: Application name (required)
+
+Note: Supported for podman runtime only.
+`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.CheckPodmanPlatformSupport(vars.RuntimeFactory.GetRuntimeType()); err != nil {
+			return err
+		}
+
+		flagValidator := buildUpgradeFlagValidator()
+		if err := flagValidator.Validate(cmd); err != nil {
+			return err
+		}
+
+		return utils.VerifyAppName(args[0])
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		applicationName := args[0]
+
+		// Once precheck passes, silence usage for any *later* internal errors.
+		cmd.SilenceUsage = true
+
+		// Create application instance using factory
+		factory := application.NewFactory(vars.RuntimeFactory.GetRuntimeType())
+		app, err := factory.Create(applicationName)
+		if err != nil {
+			return fmt.Errorf("failed to create application instance: %w", err)
+		}
+
+		opts := appTypes.UpgradeOptions{
+			Name:              applicationName,
+			ArgParams:         upgradeArgParams,
+			ValuesFiles:       upgradeValuesFiles,
+			DryRun:            upgradeDryRun,
+			SkipModelDownload: upgradeSkipModelDownload,
+			ImagePullPolicy:   image.ImagePullPolicy(upgradeImagePullPolicy),
+		}
+
+		return app.Upgrade(context.Background(), opts)
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().StringSliceVar(
+		&upgradeRawArgParams,
+		appFlags.Upgrade.Params,
+		[]string{},
+		"Inline parameters to configure the application.\n\n"+
+			"Format:\n"+
+			"- Comma-separated key=value pairs\n"+
+			"- Example: --params key1=value1,key2=value2\n\n"+
+			"Precedence:\n"+
+			"- When both --values and --params are provided, --params overrides --values\n",
+	)
+
+	upgradeCmd.Flags().StringArrayVarP(
+		&upgradeValuesFiles,
+		appFlags.Upgrade.Values,
+		"f",
+		[]string{},
+		"Specify values files to override default template values.\n\n"+
+			"Usage:\n"+
+			"- Can be provided multiple times; files are applied in order and later files override earlier ones\n",
+	)
+
+	upgradeCmd.Flags().BoolVar(
+		&upgradeDryRun,
+		appFlags.Upgrade.DryRun,
+		false,
+		"Print the diff of the pods to be replaced or created, without upgrading them\n",
+	)
+
+	upgradeCmd.Flags().BoolVar(
+		&upgradeSkipModelDownload,
+		appFlags.Upgrade.SkipModelDownload,
+		false,
+		"Skip model download during application upgrade\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+
+	upgradeCmd.Flags().StringVar(
+		&upgradeImagePullPolicy,
+		appFlags.Upgrade.ImagePullPolicy,
+		string(image.PullIfNotPresent),
+		"Image pull policy for container images required for given application. Supported values: Always, Never, IfNotPresent.\n\n"+
+			"Note: Supported for podman runtime only.\n",
+	)
+}
+
+// buildUpgradeFlagValidator creates and configures the flag validator for the upgrade command.
+func buildUpgradeFlagValidator() *flagvalidator.FlagValidator {
+	builder := flagvalidator.NewFlagValidatorBuilder(vars.RuntimeFactory.GetRuntimeType())
+
+	builder.
+		AddCommonFlag(appFlags.Upgrade.Params, validateUpgradeParamsFlag).
+		AddCommonFlag(appFlags.Upgrade.Values, validateUpgradeValuesFlag).
+		AddCommonFlag(appFlags.Upgrade.DryRun, nil)
+
+	builder.
+		AddPodmanFlag(appFlags.Upgrade.SkipModelDownload, nil).
+		AddPodmanFlag(appFlags.Upgrade.ImagePullPolicy, validateUpgradeImagePullPolicyFlag)
+
+	return builder.Build()
+}
+
+// validateUpgradeParamsFlag parses the params flag, the params are validated against the
+// values of the application template once the template of the application is known.
+func validateUpgradeParamsFlag(cmd *cobra.Command) error {
+	var err error
+	upgradeArgParams, err = utils.ParseKeyValues(upgradeRawArgParams)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	return nil
+}
+
+// validateUpgradeValuesFlag validates the values flag.
+func validateUpgradeValuesFlag(cmd *cobra.Command) error {
+	for _, vf := range upgradeValuesFiles {
+		if !utils.FileExists(vf) {
+			return fmt.Errorf("file '%s' does not exist", vf)
+		}
+	}
+
+	return nil
+}
+
+// validateUpgradeImagePullPolicyFlag validates the image-pull-policy flag.
+func validateUpgradeImagePullPolicyFlag(cmd *cobra.Command) error {
+	if ok := image.ImagePullPolicy(upgradeImagePullPolicy).Valid(); !ok {
+		return fmt.Errorf(
+			"invalid value %q: must be one of %q, %q, %q",
+			upgradeImagePullPolicy, image.PullAlways, image.PullNever, image.PullIfNotPresent,
+		)
+	}
+
+	return nil
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/application"
 	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/bootstrap"
 	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/catalog"
+	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/spyre"
 	"github.com/project-ai-services/ai-services/cmd/ai-services/cmd/version"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 )
 
 // RootCmd represents the base command when called without any subcommands.
@@ -40,10 +42,12 @@ func Execute() {
 
 func init() {
 	logger.Init()
+	vars.CLIVersion = version.GetVersion()
 	RootCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	RootCmd.AddCommand(version.VersionCmd)
 	RootCmd.AddCommand(bootstrap.BootstrapCmd())
 	RootCmd.AddCommand(application.ApplicationCmd)
 	RootCmd.AddCommand(catalog.CatalogCmd())
+	RootCmd.AddCommand(spyre.SpyreCmd())
 }
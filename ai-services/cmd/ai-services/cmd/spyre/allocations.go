@@ -0,0 +1,65 @@
+package spyre
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// NewAllocationsCmd returns the cobra command that lists the Spyre cards allocated to applications.
+func NewAllocationsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "allocations",
+		Short: "List the Spyre cards allocated to applications",
+		Long: `List the Spyre cards allocated to the containers of the applications, as recorded in the
+allocation ledger. The ledger is first reconciled against the Spyre cards set in the running containers.
+
+Example:
+		ai-services spyre allocations`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Once precheck passes, silence usage for any *later* internal errors.
+			cmd.SilenceUsage = true
+
+			return listAllocations()
+		},
+	}
+}
+
+func listAllocations() error {
+	runtimeClient, err := podman.NewPodmanClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to podman: %w", err)
+	}
+
+	allocations, err := spyre.List(runtimeClient)
+	if err != nil {
+		return fmt.Errorf("failed to list Spyre card allocations: %w", err)
+	}
+
+	if len(allocations) == 0 {
+		logger.Infoln("No Spyre cards are allocated")
+
+		return nil
+	}
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("PCI ADDRESS", "APPLICATION", "POD", "CONTAINER", "ALLOCATED")
+
+	for _, allocation := range allocations {
+		pod, container := allocation.Pod, allocation.Container
+		if allocation.Reserved() {
+			pod, container = fmt.Sprintf("(reserved by pid %d)", allocation.PID), "-"
+		}
+		printer.AppendRow(allocation.PCIAddress, allocation.Application, pod, container, utils.TimeAgo(allocation.AllocatedAt))
+	}
+
+	return nil
+}
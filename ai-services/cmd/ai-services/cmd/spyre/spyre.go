@@ -0,0 +1,19 @@
+package spyre
+
+import "github.com/spf13/cobra"
+
+// SpyreCmd returns the cobra command for inspecting the Spyre cards of the host.
+func SpyreCmd() *cobra.Command {
+	spyreCmd := &cobra.Command{
+		Use:   "spyre",
+		Short: "Inspect the Spyre cards of the host",
+		Long:  `Inspect the Spyre cards of the host and the applications they are allocated to`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	spyreCmd.AddCommand(NewAllocationsCmd())
+
+	return spyreCmd
+}
@@ -0,0 +1,375 @@
+// Package backup writes and reads the gzipped tar archives holding the backup of an application: a manifest
+// describing the application, its values with the secrets in clear, and the files of its data directory.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+)
+
+const (
+	// FormatVersion is the version of the layout of the archive, bumped on incompatible changes
+	FormatVersion = 1
+
+	manifestName    = "backup.json"
+	metadataName    = "state/metadata.json"
+	dataPrefix      = "data/"
+	filePermissions = 0o600 // the archive holds the secrets of the application in clear
+	dirPermissions  = 0o755
+)
+
+// Manifest describes the application a backup was taken of.
+type Manifest struct {
+	FormatVersion   int       `json:"formatVersion"`
+	Application     string    `json:"application"`
+	Template        string    `json:"template"`
+	TemplateVersion string    `json:"templateVersion"`
+	CLIVersion      string    `json:"cliVersion"`
+	CreatedAt       time.Time `json:"createdAt"`
+	// Live is set if the data was copied while the application was running
+	Live bool `json:"live"`
+}
+
+// Contents is the content of a backup archive, besides the data directory.
+type Contents struct {
+	Manifest Manifest
+	// Metadata is the stored metadata of the application, its overrides holding the secrets in clear
+	Metadata state.Metadata
+}
+
+// Write writes the backup along with the files of the data directory to the archive at the path.
+// The files for which skip returns true are left out, skipping a directory skips all of its files.
+func Write(archivePath string, b Contents, dataDir string, skip func(path string) bool) error {
+	tmp := archivePath + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to create backup archive: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	if err := writeArchive(f, b, dataDir, skip); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+
+	if err := os.Rename(tmp, archivePath); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+
+	return nil
+}
+
+func writeArchive(w io.Writer, b Contents, dataDir string, skip func(path string) bool) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	// the manifest and the metadata come first, so that they are read without reading the whole archive
+	if err := writeJSON(tw, manifestName, b.Manifest); err != nil {
+		return err
+	}
+	if err := writeJSON(tw, metadataName, b.Metadata); err != nil {
+		return err
+	}
+
+	if err := writeDir(tw, dataDir, skip); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(tw *tar.Writer, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	hdr := &tar.Header{Name: name, Mode: filePermissions, Size: int64(len(data)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeDir adds the directories, regular files and symlinks of the data directory to the archive,
+// keeping their permissions and ownership, as the containers expect to own their data.
+func writeDir(tw *tar.Writer, dataDir string, skip func(path string) bool) error {
+	return filepath.WalkDir(dataDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+
+		if p == dataDir {
+			return nil
+		}
+
+		if skip(p) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return fmt.Errorf("failed to read %s: %w", p, err)
+			}
+		case !info.IsDir() && !info.Mode().IsRegular():
+			// sockets and pipes are recreated by the containers
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", p, err)
+		}
+
+		rel, err := filepath.Rel(dataDir, p)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", p, err)
+		}
+		hdr.Name = dataPrefix + filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", p, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		return copyFile(tw, p, hdr.Size)
+	})
+}
+
+func copyFile(tw *tar.Writer, p string, size int64) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// a file written to while it is archived is cut at the size it had when its header was written
+	if _, err := io.CopyN(tw, f, size); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("file %s was truncated while it was archived, take the backup without --live", p)
+		}
+
+		return fmt.Errorf("failed to archive %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// Read returns the manifest and the metadata of the backup, without reading the data of the archive.
+func Read(archivePath string) (*Contents, error) {
+	b := &Contents{}
+	found := 0
+
+	err := walkArchive(archivePath, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		var v any
+		switch hdr.Name {
+		case manifestName:
+			v = &b.Manifest
+		case metadataName:
+			v = &b.Metadata
+		default:
+			return false, nil
+		}
+
+		if err := json.NewDecoder(r).Decode(v); err != nil {
+			return false, fmt.Errorf("failed to parse %s of the backup: %w", hdr.Name, err)
+		}
+		found++
+
+		return found < 2, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Manifest.FormatVersion == 0 {
+		return nil, fmt.Errorf("'%s' is not a backup of an application", archivePath)
+	}
+	if b.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("backup '%s' has format version %d, this release supports up to version %d",
+			archivePath, b.Manifest.FormatVersion, FormatVersion)
+	}
+
+	return b, nil
+}
+
+// Extract restores the files of the data directory of the backup into the directory, with their permissions and ownership.
+// The files are created through an os.Root of the directory, so that no entry of the archive, a symlink extracted
+// earlier included, writes outside of it.
+func Extract(archivePath, dataDir string) error {
+	if err := os.MkdirAll(dataDir, dirPermissions); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dataDir, err)
+	}
+
+	root, err := os.OpenRoot(dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", dataDir, err)
+	}
+	defer func() { _ = root.Close() }()
+
+	var dirs []*tar.Header
+
+	err = walkArchive(archivePath, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		rel, ok := strings.CutPrefix(hdr.Name, dataPrefix)
+		if !ok || rel == "" {
+			return true, nil
+		}
+
+		rel = path.Clean(rel)
+		if !filepath.IsLocal(rel) {
+			return false, fmt.Errorf("invalid path '%s' in the backup", hdr.Name)
+		}
+		target := filepath.FromSlash(rel)
+
+		if err := root.MkdirAll(filepath.Dir(target), dirPermissions); err != nil {
+			return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := root.MkdirAll(target, dirPermissions); err != nil {
+				return false, fmt.Errorf("failed to create %s: %w", target, err)
+			}
+			// the permissions of the directories are set last, as they could forbid writing their files
+			hdr.Name = target
+			dirs = append(dirs, hdr)
+
+			return true, nil
+		case tar.TypeSymlink:
+			if err := root.Symlink(hdr.Linkname, target); err != nil {
+				return false, fmt.Errorf("failed to create %s: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := extractFile(root, target, hdr, r); err != nil {
+				return false, err
+			}
+		default:
+			return true, nil
+		}
+
+		return true, restoreOwnership(root, target, hdr)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hdr := range dirs {
+		if err := root.Chmod(hdr.Name, hdr.FileInfo().Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+		}
+		if err := restoreOwnership(root, hdr.Name, hdr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractFile(root *os.Root, target string, hdr *tar.Header, r io.Reader) error {
+	f, err := root.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.CopyN(f, r, hdr.Size); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", target, err)
+	}
+
+	return nil
+}
+
+func restoreOwnership(root *os.Root, target string, hdr *tar.Header) error {
+	if err := root.Lchown(target, hdr.Uid, hdr.Gid); err != nil {
+		return fmt.Errorf("failed to restore the ownership of %s: %w", target, err)
+	}
+
+	if hdr.Typeflag == tar.TypeSymlink {
+		return nil
+	}
+
+	if err := root.Chtimes(target, hdr.ModTime, hdr.ModTime); err != nil {
+		return fmt.Errorf("failed to restore the modification time of %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// walkArchive calls fn for each entry of the archive, until fn returns false or an error.
+func walkArchive(archivePath string, fn func(hdr *tar.Header, r io.Reader) (bool, error)) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read backup archive '%s': %w", archivePath, err)
+	}
+	defer func() { _ = gr.Close() }()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup archive '%s': %w", archivePath, err)
+		}
+
+		more, err := fn(hdr, tr)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testArchive builds a backup archive, its entries owned by the current user so that they can be extracted unprivileged.
+type testArchive struct {
+	t  *testing.T
+	tw *tar.Writer
+}
+
+func (a *testArchive) add(hdr *tar.Header, body string) {
+	a.t.Helper()
+
+	hdr.Uid, hdr.Gid, hdr.ModTime = os.Getuid(), os.Getgid(), time.Unix(0, 0)
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		a.t.Fatal(err)
+	}
+	if _, err := a.tw.Write([]byte(body)); err != nil {
+		a.t.Fatal(err)
+	}
+}
+
+func (a *testArchive) file(name, body string) {
+	a.add(&tar.Header{Name: name, Mode: filePermissions, Size: int64(len(body)), Typeflag: tar.TypeReg}, body)
+}
+
+func (a *testArchive) dir(name string) {
+	a.add(&tar.Header{Name: name, Mode: dirPermissions, Typeflag: tar.TypeDir}, "")
+}
+
+func (a *testArchive) symlink(name, target string) {
+	a.add(&tar.Header{Name: name, Linkname: target, Typeflag: tar.TypeSymlink}, "")
+}
+
+// writeTestArchive writes the gzipped archive built by build to a temporary directory.
+func writeTestArchive(t *testing.T, build func(a *testArchive)) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gw := gzip.NewWriter(f)
+	a := &testArchive{t: t, tw: tar.NewWriter(gw)}
+	build(a)
+	if err := a.tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return archivePath
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(a *testArchive, outside string)
+		// files maps the paths of the data directory extracted to their content
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "data files",
+			build: func(a *testArchive, _ string) {
+				a.file(manifestName, "{}")
+				a.dir("data/db/")
+				a.file("data/db/index.bin", "index")
+				a.file("data/config.yaml", "key: value")
+				a.symlink("data/current", "db/index.bin")
+			},
+			files: map[string]string{"db/index.bin": "index", "config.yaml": "key: value", "current": "index"},
+		},
+		{
+			name:  "cleaned path",
+			build: func(a *testArchive, _ string) { a.file("data/db/../config.yaml", "key: value") },
+			files: map[string]string{"config.yaml": "key: value"},
+		},
+		{
+			name: "entries outside the data directory",
+			build: func(a *testArchive, _ string) {
+				a.file("values.yaml", "secret")
+				a.dir("data")
+			},
+		},
+		{
+			name:    "parent path",
+			build:   func(a *testArchive, _ string) { a.file("data/../escaped", "x") },
+			wantErr: "invalid path",
+		},
+		{
+			name:    "nested parent path",
+			build:   func(a *testArchive, _ string) { a.file("data/db/../../escaped", "x") },
+			wantErr: "invalid path",
+		},
+		{
+			name:    "absolute path",
+			build:   func(a *testArchive, _ string) { a.file("data//etc/escaped", "x") },
+			wantErr: "invalid path",
+		},
+		{
+			name: "file through a symlink leaving the data directory",
+			build: func(a *testArchive, outside string) {
+				a.symlink("data/link", outside)
+				a.file("data/link/escaped", "x")
+			},
+			wantErr: "escapes",
+		},
+		{
+			name: "directory through a symlink leaving the data directory",
+			build: func(a *testArchive, _ string) {
+				a.symlink("data/link", "../")
+				a.file("data/link/sub/escaped", "x")
+			},
+			wantErr: "escapes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			dataDir, outside := filepath.Join(base, "app"), filepath.Join(base, "outside")
+			if err := os.Mkdir(outside, dirPermissions); err != nil {
+				t.Fatal(err)
+			}
+
+			err := Extract(writeTestArchive(t, func(a *testArchive) { tt.build(a, outside) }), dataDir)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Extract() failed: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("Extract() succeeded, want an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("Extract() error %q does not contain %q", err, tt.wantErr)
+			}
+
+			for name, body := range tt.files {
+				data, err := os.ReadFile(filepath.Join(dataDir, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != body {
+					t.Errorf("extracted %s = %q, want %q", name, data, body)
+				}
+			}
+
+			// nothing is ever written next to the data directory
+			for _, dir := range []string{base, outside} {
+				entries, err := os.ReadDir(dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, e := range entries {
+					if e.Name() != "app" && e.Name() != "outside" {
+						t.Errorf("Extract() wrote %s outside the data directory", filepath.Join(dir, e.Name()))
+					}
+				}
+			}
+		})
+	}
+}
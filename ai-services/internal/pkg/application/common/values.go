@@ -0,0 +1,21 @@
+package common
+
+import (
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+)
+
+// PrintValues prints the values of an application as YAML.
+func PrintValues(values map[string]any) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	logger.Infoln("Values:\n" + string(data))
+
+	return nil
+}
@@ -12,6 +12,9 @@ type Application interface {
 	// Create deploys a new application based on a template.
 	Create(ctx context.Context, opts types.CreateOptions) error
 
+	// Upgrade re-renders a deployed application and replaces the parts which changed.
+	Upgrade(ctx context.Context, opts types.UpgradeOptions) error
+
 	// Delete removes an application and its associated resources.
 	Delete(ctx context.Context, opts types.DeleteOptions) error
 
@@ -27,6 +30,18 @@ type Application interface {
 	// Info displays detailed information about an application.
 	Info(opts types.InfoOptions) error
 
+	// GetValues returns the values the application is deployed with.
+	GetValues(opts types.GetValuesOptions) (map[string]any, error)
+
+	// Diff returns the drift of a deployed application from what it is expected to run.
+	Diff(ctx context.Context, opts types.DiffOptions) ([]types.Drift, error)
+
+	// Backup writes the data and the values of an application to an archive.
+	Backup(ctx context.Context, opts types.BackupOptions) error
+
+	// Restore recreates an application along with its data from a backup archive.
+	Restore(ctx context.Context, opts types.RestoreOptions) error
+
 	// Logs displays logs from an application pod.
 	Logs(opts types.LogsOptions) error
 
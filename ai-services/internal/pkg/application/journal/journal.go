@@ -0,0 +1,237 @@
+// Package journal records the progress of an application create on disk, so that an
+// interrupted or failed create can be resumed from the last completed step.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+)
+
+const (
+	journalFileName = "journal.json"
+	dirPermissions  = 0o755
+	filePermissions = 0o644
+)
+
+// ErrNotFound is returned when no journal exists for the application.
+var ErrNotFound = errors.New("no deployment journal found")
+
+// Step is an application level step of the create.
+type Step string
+
+const (
+	StepImagesPulled     Step = "images-pulled"
+	StepModelsDownloaded Step = "models-downloaded"
+	StepCardsAllocated   Step = "cards-allocated"
+	StepCompleted        Step = "completed"
+)
+
+// Pod holds the progress of a single pod template.
+type Pod struct {
+	// Name is the name of the pod created from the template
+	Name string `json:"name"`
+	// SpyreCards maps a container name to the PCI addresses of the Spyre cards assigned to it
+	SpyreCards map[string][]string `json:"spyreCards,omitempty"`
+	PlayedAt   time.Time           `json:"playedAt,omitzero"`
+	ReadyAt    time.Time           `json:"readyAt,omitzero"`
+}
+
+// Journal is the deployment journal of an application, stored under
+// /var/lib/ai-services/applications/<name>/journal.json.
+type Journal struct {
+	Application string             `json:"application"`
+	Template    string             `json:"template"`
+	StartedAt   time.Time          `json:"startedAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+	Steps       map[Step]time.Time `json:"steps"`
+	// SpyreCards are the PCI addresses of the free Spyre cards allocated for the create
+	SpyreCards []string `json:"spyreCards,omitempty"`
+	// Pods maps a pod template to its progress
+	Pods map[string]*Pod `json:"pods"`
+	// Overrides are the values the create is run with, the secrets encrypted, reused on resume as the
+	// application state is only stored once the create completes
+	Overrides *state.Overrides `json:"overrides,omitempty"`
+
+	mu   sync.Mutex
+	path string
+}
+
+// Path returns the path of the journal file for the given application.
+func Path(appName string) string {
+	return filepath.Join(constants.ApplicationsPath, filepath.Base(appName), journalFileName)
+}
+
+// New starts a new journal for the application, replacing any previous one.
+func New(appName, templateName string) (*Journal, error) {
+	now := time.Now()
+	j := &Journal{
+		Application: appName,
+		Template:    templateName,
+		StartedAt:   now,
+		Steps:       map[Step]time.Time{},
+		Pods:        map[string]*Pod{},
+		path:        Path(appName),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), dirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create application directory: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j, j.save()
+}
+
+// Load reads the journal of the application, returns ErrNotFound if there is none.
+func Load(appName string) (*Journal, error) {
+	path := Path(appName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to read deployment journal: %w", err)
+	}
+
+	j := &Journal{}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("failed to parse deployment journal '%s': %w", path, err)
+	}
+
+	if j.Steps == nil {
+		j.Steps = map[Step]time.Time{}
+	}
+	if j.Pods == nil {
+		j.Pods = map[string]*Pod{}
+	}
+	j.path = path
+
+	return j, nil
+}
+
+// Done reports whether the step is completed.
+func (j *Journal) Done(step Step) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	_, ok := j.Steps[step]
+
+	return ok
+}
+
+// MarkDone records the step as completed.
+func (j *Journal) MarkDone(step Step) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Steps[step] = time.Now()
+
+	return j.save()
+}
+
+// MarkCardsAllocated records the Spyre cards allocated for the create.
+func (j *Journal) MarkCardsAllocated(pciAddresses []string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.SpyreCards = pciAddresses
+	j.Steps[StepCardsAllocated] = time.Now()
+
+	return j.save()
+}
+
+// SetOverrides records the values the create is run with, their secrets already encrypted.
+func (j *Journal) SetOverrides(overrides state.Overrides) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Overrides = &overrides
+
+	return j.save()
+}
+
+// Pod returns a copy of the progress of the pod template, nil if it is not recorded.
+func (j *Journal) Pod(podTemplate string) *Pod {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pod, ok := j.Pods[podTemplate]
+	if !ok {
+		return nil
+	}
+	cp := *pod
+
+	return &cp
+}
+
+// MarkPodPlayed records that the pod has been created from the pod template.
+func (j *Journal) MarkPodPlayed(podTemplate, podName string, spyreCards map[string][]string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Pods[podTemplate] = &Pod{Name: podName, SpyreCards: spyreCards, PlayedAt: time.Now()}
+
+	return j.save()
+}
+
+// MarkPodReady records that the pod created from the pod template passed its readiness checks.
+func (j *Journal) MarkPodReady(podTemplate string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pod, ok := j.Pods[podTemplate]
+	if !ok {
+		return fmt.Errorf("pod template '%s' is not recorded as played", podTemplate)
+	}
+	pod.ReadyAt = time.Now()
+
+	return j.save()
+}
+
+// ForgetPod drops the progress of the pod created with the given name, as the pod has been removed.
+func (j *Journal) ForgetPod(podName string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for podTemplate, pod := range j.Pods {
+		if pod.Name == podName {
+			delete(j.Pods, podTemplate)
+		}
+	}
+	delete(j.Steps, StepCompleted)
+
+	return j.save()
+}
+
+// save writes the journal to a temporary file and renames it, so that an interrupted write
+// never leaves a corrupted journal behind. The caller must hold the lock.
+func (j *Journal) save() error {
+	j.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal deployment journal: %w", err)
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePermissions); err != nil {
+		return fmt.Errorf("failed to write deployment journal: %w", err)
+	}
+
+	if err := os.Rename(tmp, j.path); err != nil {
+		return fmt.Errorf("failed to write deployment journal: %w", err)
+	}
+
+	return nil
+}
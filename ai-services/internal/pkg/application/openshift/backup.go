@@ -0,0 +1,23 @@
+package openshift
+
+import (
+	"context"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+)
+
+// Backup writes the data and the values of an application to an archive.
+func (o *OpenshiftApplication) Backup(_ context.Context, opts types.BackupOptions) error {
+	logger.Warningf("Not implemented, the data of application '%s' is stored in its persistent volume claims, "+
+		"back them up with the snapshots of the storage class\n", opts.Name)
+
+	return nil
+}
+
+// Restore recreates an application along with its data from a backup archive.
+func (o *OpenshiftApplication) Restore(_ context.Context, _ types.RestoreOptions) error {
+	logger.Warningln("Not implemented, backups are only supported for podman runtime")
+
+	return nil
+}
@@ -21,6 +21,10 @@ func (o *OpenshiftApplication) Create(ctx context.Context, opts types.CreateOpti
 
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 
+	if opts.DryRun {
+		return dryRun(tp, opts)
+	}
+
 	// Step1: Fetch the operation timeout
 	timeout, err := getOperationTimeout(ctx, tp, opts)
 	if err != nil {
@@ -39,14 +43,21 @@ func (o *OpenshiftApplication) Create(ctx context.Context, opts types.CreateOpti
 		return fmt.Errorf("failed to prepare values: %w", err)
 	}
 
-	// Step4: Deploy Application
+	// Step4: Download the models to the models PVC
+	if !opts.SkipModelDownload {
+		if err := o.downloadModels(ctx, opts, values, timeout); err != nil {
+			return err
+		}
+	}
+
+	// Step5: Deploy Application
 	if err := deployApp(ctx, chart, timeout, values, opts); err != nil {
 		return err
 	}
 
 	logger.Infoln("-------")
 
-	// Step5: Print the next steps to be performed at the end of create
+	// Step6: Print the next steps to be performed at the end of create
 	if err := helpers.PrintNextSteps(tp, o.runtime, opts.Name, opts.TemplateName); err != nil {
 		// do not want to fail the overall create if we cannot print next steps
 		logger.Infof("failed to display next steps: %v\n", err)
@@ -0,0 +1,141 @@
+package openshift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/helm"
+)
+
+const (
+	driftMissing = "<missing>"
+	secretKind   = "Secret"
+)
+
+// Diff compares the objects of the manifest of the Helm release with the objects live in the cluster.
+// Only the fields set in the manifest are compared, as the live objects also hold the defaults and the
+// status set by the cluster. The values of the secrets are redacted.
+func (o *OpenshiftApplication) Diff(_ context.Context, opts types.DiffOptions) ([]types.Drift, error) {
+	helmClient, err := helm.NewHelm(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := helmClient.ReleaseObjects(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []types.Drift
+	for _, obj := range objects {
+		resourceName := obj.Kind + "/" + obj.Name
+		if obj.Live == nil {
+			drifts = append(drifts, types.Drift{Resource: resourceName, Field: "object", Expected: "present", Actual: driftMissing})
+
+			continue
+		}
+
+		manifest := maps.Clone(obj.Manifest)
+		// the status is set by the cluster, and the string data of a secret is stored in its data
+		delete(manifest, "status")
+		delete(manifest, "stringData")
+
+		compareFields("", manifest, obj.Live, obj.Kind == secretKind, func(field, expected, actual string) {
+			drifts = append(drifts, types.Drift{Resource: resourceName, Field: field, Expected: expected, Actual: actual})
+		})
+	}
+
+	return drifts, nil
+}
+
+// compareFields reports the fields set in expected which differ in actual.
+func compareFields(path string, expected, actual any, secret bool, drift func(field, expected, actual string)) {
+	switch exp := expected.(type) {
+	case nil:
+		return
+	case map[string]any:
+		act, ok := actual.(map[string]any)
+		if !ok {
+			drift(path, formatField(exp, secret), formatField(actual, secret))
+
+			return
+		}
+
+		// an env var or a volume named after a secret holds a secret
+		if name, ok := exp["name"].(string); ok && state.IsSecretKey(name) {
+			secret = true
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(exp)) {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			compareFields(fieldPath, exp[key], act[key], secret, drift)
+		}
+	case []any:
+		act, ok := actual.([]any)
+		if !ok || len(act) != len(exp) {
+			drift(path, formatField(exp, secret), formatField(actual, secret))
+
+			return
+		}
+
+		for i := range exp {
+			compareFields(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i], secret, drift)
+		}
+	default:
+		if !equalScalars(exp, actual) {
+			drift(path, formatField(exp, secret), formatField(actual, secret))
+		}
+	}
+}
+
+// equalScalars compares the values as strings, the quantities by their value as the cluster normalizes them (Eg:- 1000m is 1).
+func equalScalars(expected, actual any) bool {
+	if fmt.Sprint(expected) == fmt.Sprint(actual) {
+		return true
+	}
+
+	expStr, ok1 := expected.(string)
+	actStr, ok2 := actual.(string)
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	expQty, err1 := resource.ParseQuantity(expStr)
+	actQty, err2 := resource.ParseQuantity(actStr)
+
+	return err1 == nil && err2 == nil && expQty.Cmp(actQty) == 0
+}
+
+func formatField(value any, secret bool) string {
+	switch v := value.(type) {
+	case nil:
+		return driftMissing
+	case map[string]any, []any:
+		if secret {
+			return state.Redacted
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+
+		return string(data)
+	default:
+		if secret {
+			return state.Redacted
+		}
+
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,39 @@
+package openshift
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/helm"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+)
+
+// dryRun renders the chart of the application locally, like 'helm template', and prints the manifests to stdout.
+// Nothing is sent to the cluster.
+func dryRun(tp templates.Template, opts types.CreateOptions) error {
+	logger.Infof("Dry run: nothing will be deployed for application '%s'\n", opts.Name)
+
+	chart, err := tp.LoadChart(opts.TemplateName)
+	if err != nil {
+		return fmt.Errorf("failed to load the chart: %w", err)
+	}
+
+	values, err := tp.LoadValues(opts.TemplateName, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return fmt.Errorf("failed to prepare values: %w", err)
+	}
+
+	// the namespace of an application is named after it
+	manifests, err := helm.Template(opts.Name, opts.Name, chart, values)
+	if err != nil {
+		return fmt.Errorf("failed to render the chart: %w", err)
+	}
+
+	if _, err := fmt.Fprint(os.Stdout, manifests); err != nil {
+		return fmt.Errorf("failed to print the rendered manifests: %w", err)
+	}
+
+	return nil
+}
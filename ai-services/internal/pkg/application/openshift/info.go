@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/common"
 	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
@@ -42,6 +43,17 @@ func (o *OpenshiftApplication) Info(opts types.InfoOptions) error {
 	version := pods[0].Labels[string(vars.VersionLabel)]
 	logger.Infoln("Version: " + version)
 
+	if opts.ShowValues {
+		values, err := o.GetValues(types.GetValuesOptions{Name: opts.Name, All: true})
+		if err != nil {
+			return err
+		}
+
+		if err := common.PrintValues(values); err != nil {
+			return err
+		}
+	}
+
 	// Step3: Read and print the info.md file
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 
@@ -0,0 +1,235 @@
+package openshift
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelsource"
+	"github.com/project-ai-services/ai-services/internal/pkg/progress"
+	runtimeTypes "github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+const (
+	// modelsPVCName is the claim the models are downloaded to, served from by the InferenceServices
+	modelsPVCName           = "models"
+	modelsMountPath         = "/models"
+	defaultModelStorageSize = "100Gi"
+	// jobNameMaxLength is the maximum length of the name of a Job, a DNS-1123 label
+	jobNameMaxLength  = 63
+	jobNameHashLength = 8
+	jobNamePrefix     = "model-"
+)
+
+// invalidJobNameChars matches the characters not allowed in the name of a Job.
+var invalidJobNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// downloadModels downloads the models of the InferenceServices served from the Hugging Face hub to the models PVC,
+// each with a Job running hf download in the tool image, and points the InferenceServices to the PVC. The progress is
+// parsed from the logs of the Jobs. The models of the other sources are left to KServe to download.
+// The downloads fail once the timeout elapsed, the Jobs still running being deleted.
+func (o *OpenshiftApplication) downloadModels(ctx context.Context, opts types.CreateOptions, values map[string]any,
+	timeout time.Duration) error {
+	models, err := hfModels(values)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	size, storageClass := modelStorage(values)
+	err = o.runtime.EnsurePVC(runtimeTypes.PVC{
+		Name:         modelsPVCName,
+		Size:         size,
+		StorageClass: storageClass,
+		Labels: map[string]string{
+			"ai-services.io/application": opts.Name,
+			"ai-services.io/template":    opts.TemplateName,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the PVC of the models: %w", err)
+	}
+
+	toolImage, err := image.ResolveToolImage("")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	keys := slices.Sorted(maps.Keys(models))
+	tracker := progress.New(fmt.Sprintf("Downloading %d models:", len(keys)))
+	items := make(map[string]*progress.Item, len(keys))
+	for _, key := range keys {
+		logger.Infof("Downloading model %s to PVC '%s'\n", models[key].Name, modelsPVCName)
+		items[key] = tracker.Add(models[key].Name)
+	}
+
+	tracker.Start()
+	err = utils.ForEachParallel(keys, vars.MaxParallelDownloads, func(key string) error {
+		model := models[key]
+		err := utils.Retry(vars.RetryCount, vars.RetryInterval, nil, func() error {
+			err := o.runModelJob(ctx, opts.Name, toolImage, model, items[key].Update)
+			// a download past the timeout is not retried
+			if err != nil && ctx.Err() != nil {
+				return utils.Permanent(fmt.Errorf("timed out after %s: %w", timeout, err))
+			}
+
+			return err
+		})
+		if err != nil {
+			items[key].Fail()
+
+			return fmt.Errorf("failed to download model %s: %w", model.Name, err)
+		}
+		items[key].Done()
+
+		return nil
+	})
+	tracker.Stop()
+
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		utils.SetNestedValue(values, key+".storageUri", fmt.Sprintf("pvc://%s/%s", modelsPVCName, models[key].Name))
+		logger.Infof("Model downloaded successfully: %s\n", models[key].Name)
+	}
+
+	return nil
+}
+
+// runModelJob runs the Job downloading the model to the models PVC, the progress parsed from its logs.
+func (o *OpenshiftApplication) runModelJob(ctx context.Context, app, toolImage string, model modelsource.Model,
+	progress func(current, total int64)) error {
+	command := []string{"hf", "download", model.Name, "--local-dir", modelsMountPath + "/" + model.Name}
+	if model.Revision != "" {
+		command = append(command, "--revision", model.Revision)
+	}
+
+	job := runtimeTypes.Job{
+		Name:    modelJobName(model.Name),
+		Image:   toolImage,
+		Command: command,
+		// the pods of the restricted SCC run as an arbitrary user, without a writable home directory
+		Env:     map[string]string{"HF_HOME": "/tmp/huggingface"},
+		Labels:  map[string]string{"ai-services.io/application": app},
+		Volumes: map[string]string{modelsPVCName: modelsMountPath},
+		// hf download draws its progress bars on a terminal only
+		TTY: true,
+	}
+
+	output := helpers.NewHFProgressWriter(progress)
+	if err := o.runtime.RunJob(ctx, job, output); err != nil {
+		if last := output.LastLine(); last != "" {
+			return fmt.Errorf("%w: %s", err, last)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// hfModels returns the models of the InferenceServices of the values served from the Hugging Face hub, by the key of
+// the values holding their storageUri.
+func hfModels(values map[string]any) (map[string]modelsource.Model, error) {
+	models := map[string]modelsource.Model{}
+	for key := range values {
+		uri, ok := utils.GetNestedValue(values, key+".storageUri")
+		if !ok {
+			continue
+		}
+
+		spec, ok := uri.(string)
+		if !ok || !strings.HasPrefix(spec, modelsource.SchemeHF+"://") {
+			continue
+		}
+
+		model, err := modelsource.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s.storageUri: %w", key, err)
+		}
+		models[key] = model
+	}
+
+	return models, nil
+}
+
+// modelStorage returns the size and the storage class of the models PVC set in the values.
+func modelStorage(values map[string]any) (string, string) {
+	size := defaultModelStorageSize
+	if v, ok := utils.GetNestedValue(values, "modelStorage.size"); ok {
+		if s, ok := v.(string); ok && s != "" {
+			size = s
+		}
+	}
+
+	storageClass := ""
+	if v, ok := utils.GetNestedValue(values, "modelStorage.storageClassName"); ok {
+		storageClass, _ = v.(string)
+	}
+
+	return size, storageClass
+}
+
+// modelJobName returns the name of the Job downloading the model, made of the model name, a DNS-1123 label once
+// sanitized and truncated, and of a hash of the model name keeping the names of the models unique.
+func modelJobName(model string) string {
+	sum := sha256.Sum256([]byte(model))
+	hash := hex.EncodeToString(sum[:])[:jobNameHashLength]
+
+	name := invalidJobNameChars.ReplaceAllString(strings.ToLower(model), "-")
+	maxLength := jobNameMaxLength - len(jobNamePrefix) - len(hash) - 1
+	if len(name) > maxLength {
+		name = name[:maxLength]
+	}
+
+	return jobNamePrefix + strings.Trim(name, "-") + "-" + hash
+}
+
+// DownloadModels downloads the models of the application template to the models PVC of the application, with the
+// Jobs create runs, bounded by the timeout of the options or else of the template.
+func (o *OpenshiftApplication) DownloadModels(ctx context.Context, opts types.CreateOptions) error {
+	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
+
+	timeout, err := getOperationTimeout(ctx, tp, opts)
+	if err != nil {
+		return err
+	}
+
+	values, err := tp.LoadValues(opts.TemplateName, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return fmt.Errorf("failed to prepare values: %w", err)
+	}
+
+	models, err := hfModels(values)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		logger.Infof("Application template '%s' has no model to download, its models are downloaded by KServe\n", opts.TemplateName)
+
+		return nil
+	}
+
+	return o.downloadModels(ctx, opts, values, timeout)
+}
@@ -0,0 +1,15 @@
+package openshift
+
+import (
+	"context"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+)
+
+// Upgrade re-renders a deployed application and replaces the parts which changed.
+func (o *OpenshiftApplication) Upgrade(_ context.Context, opts types.UpgradeOptions) error {
+	logger.Warningf("Not implemented, 'ai-services application create %s' upgrades the existing Helm release instead\n", opts.Name)
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package openshift
+
+import (
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/helm"
+)
+
+// GetValues returns the values the application is deployed with, from its Helm release.
+func (o *OpenshiftApplication) GetValues(opts types.GetValuesOptions) (map[string]any, error) {
+	helmClient, err := helm.NewHelm(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := helmClient.GetValues(opts.Name, opts.All)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.ShowSecrets {
+		return state.RedactValues(values), nil
+	}
+
+	return values, nil
+}
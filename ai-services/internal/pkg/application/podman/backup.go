@@ -0,0 +1,224 @@
+package podman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/backup"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/journal"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	runtimeTypes "github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+const (
+	podStatusRunning  = "Running"
+	podStatusDegraded = "Degraded"
+)
+
+// Backup writes the data directory of the application, along with the values and the template it is deployed with,
+// to a gzipped tar archive. Unless live is set, the running pods are stopped while the data is copied, so that the
+// copy is consistent, and started again once it is done.
+func (p *PodmanApplication) Backup(_ context.Context, opts types.BackupOptions) error {
+	templateName, pods, err := p.deployedApplication(opts.Name)
+	if err != nil {
+		return err
+	}
+
+	contents, err := backupContents(opts.Name, templateName, opts.Live)
+	if err != nil {
+		return err
+	}
+
+	if !opts.Live {
+		restart, proceed, err := p.quiesce(opts.Name, pods, opts.AutoYes)
+		if err != nil || !proceed {
+			return err
+		}
+		defer restart()
+	}
+
+	logger.Infof("Backing up application '%s' to '%s'...\n", opts.Name, opts.Output)
+
+	skipped := append(state.Files(opts.Name), journal.Path(opts.Name))
+	if err := backup.Write(opts.Output, *contents, state.Dir(opts.Name), func(path string) bool {
+		return slices.Contains(skipped, path)
+	}); err != nil {
+		return err
+	}
+
+	logger.Infof("Application '%s' backed up to '%s', the archive holds the secrets of the application in clear\n",
+		opts.Name, opts.Output)
+
+	return nil
+}
+
+// quiesce stops the running pods of the application, once confirmed, and returns the function starting them again.
+func (p *PodmanApplication) quiesce(appName string, pods []runtimeTypes.Pod, autoYes bool) (func(), bool, error) {
+	running := slices.DeleteFunc(slices.Clone(pods), func(pod runtimeTypes.Pod) bool {
+		return pod.Status != podStatusRunning && pod.Status != podStatusDegraded
+	})
+	if len(running) == 0 {
+		return func() {}, true, nil
+	}
+
+	if !autoYes {
+		confirmed, err := utils.ConfirmAction(fmt.Sprintf("The %d running pods of application '%s' are stopped during the backup, proceed? ",
+			len(running), appName))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to take user input: %w", err)
+		}
+		if !confirmed {
+			logger.Infoln("Backup cancelled")
+
+			return nil, false, nil
+		}
+	}
+
+	restart := func() {
+		if err := p.startPods(running); err != nil {
+			logger.Warningf("failed to start the pods of application '%s' again: %v\n", appName, err)
+		}
+	}
+
+	if err := p.stopPods(running); err != nil {
+		// start the pods which were stopped before the failure
+		restart()
+
+		return nil, false, err
+	}
+
+	return restart, true, nil
+}
+
+// backupContents returns the manifest and the metadata of the backup, the secrets of the metadata in clear so that
+// the backup can be restored on a host which does not have the key they are encrypted with.
+func backupContents(appName, templateName string, live bool) (*backup.Contents, error) {
+	m, err := state.Load(appName)
+	if errors.Is(err, state.ErrNotFound) {
+		logger.Warningf("No values are stored for application '%s', it is restored with the default values of template '%s'\n",
+			appName, templateName)
+		m = &state.Metadata{Application: appName, Template: templateName}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if m.Overrides, err = m.Overrides.Reveal(); err != nil {
+		return nil, fmt.Errorf("failed to read the stored values of application '%s': %w", appName, err)
+	}
+
+	return &backup.Contents{
+		Manifest: backup.Manifest{
+			FormatVersion:   backup.FormatVersion,
+			Application:     appName,
+			Template:        m.Template,
+			TemplateVersion: m.TemplateVersion,
+			CLIVersion:      vars.CLIVersion,
+			CreatedAt:       time.Now(),
+			Live:            live,
+		},
+		Metadata: *m,
+	}, nil
+}
+
+// Restore extracts the data directory of the backup, and creates the application with the template
+// and the values it was backed up with. The application must not exist on the host.
+func (p *PodmanApplication) Restore(ctx context.Context, opts types.RestoreOptions) error {
+	contents, err := backup.Read(opts.Input)
+	if err != nil {
+		return err
+	}
+
+	appName := contents.Manifest.Application
+	if err := p.verifyRestoreTarget(appName); err != nil {
+		return err
+	}
+
+	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
+	if err := tp.AppTemplateExist(contents.Manifest.Template); err != nil {
+		return err
+	}
+
+	appMetadata, err := tp.LoadMetadata(contents.Manifest.Template, true)
+	if err != nil {
+		return fmt.Errorf("failed to read the app metadata: %w", err)
+	}
+	if contents.Manifest.TemplateVersion != "" && appMetadata.Version != contents.Manifest.TemplateVersion {
+		logger.Warningf("Application '%s' was backed up with version %s of template '%s', it is restored with version %s\n",
+			appName, contents.Manifest.TemplateVersion, contents.Manifest.Template, appMetadata.Version)
+	}
+
+	logger.Infof("Restoring the data of application '%s' backed up at %s...\n", appName, contents.Manifest.CreatedAt.Format(time.RFC3339))
+	if err := backup.Extract(opts.Input, state.Dir(appName)); err != nil {
+		return removeRestoredData(appName, fmt.Errorf("failed to restore the data of application '%s': %w", appName, err))
+	}
+
+	overrides := contents.Metadata.Overrides
+	createOpts := types.CreateOptions{
+		Name:              appName,
+		TemplateName:      contents.Manifest.Template,
+		ArgParams:         overrides.Params,
+		SkipModelDownload: opts.SkipModelDownload,
+		ImagePullPolicy:   opts.ImagePullPolicy,
+	}
+
+	if len(overrides.Values) > 0 {
+		valuesFile, err := overrides.WriteValuesFile(appName)
+		if err != nil {
+			return removeRestoredData(appName, err)
+		}
+		defer func() { _ = os.Remove(valuesFile) }()
+		createOpts.ValuesFiles = []string{valuesFile}
+	}
+
+	if err := p.Create(ctx, createOpts); err != nil {
+		return removeRestoredData(appName, err)
+	}
+
+	return nil
+}
+
+// removeRestoredData removes the data directory of an application whose restore failed, so that the restore can be
+// run again, and returns the error which interrupted it.
+func removeRestoredData(appName string, err error) error {
+	if rmErr := os.RemoveAll(state.Dir(appName)); rmErr != nil {
+		logger.Warningf("failed to remove the data of application '%s': %v\n", appName, rmErr)
+	}
+
+	return err
+}
+
+// verifyRestoreTarget checks that neither the pods nor the data of the application exist on the host.
+func (p *PodmanApplication) verifyRestoreTarget(appName string) error {
+	pods, err := p.runtime.ListPods(map[string][]string{
+		"label": {fmt.Sprintf("ai-services.io/application=%s", appName)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	if len(pods) > 0 {
+		return fmt.Errorf("application '%s' already exists, delete it before restoring it", appName)
+	}
+
+	entries, err := os.ReadDir(state.Dir(appName))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read the data directory of application '%s': %w", appName, err)
+	}
+
+	if len(entries) > 0 {
+		return fmt.Errorf("the data directory '%s' of application '%s' already exists, "+
+			"delete the application along with its data before restoring it", state.Dir(appName), appName)
+	}
+
+	return nil
+}
@@ -5,12 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
-	"sync"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/journal"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
 	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
 	clipodman "github.com/project-ai-services/ai-services/internal/pkg/cli/podman"
@@ -21,13 +25,16 @@ import (
 	"github.com/project-ai-services/ai-services/internal/pkg/models"
 	"github.com/project-ai-services/ai-services/internal/pkg/specs"
 	"github.com/project-ai-services/ai-services/internal/pkg/spinner"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
 	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 )
 
-var (
-	envMutex sync.Mutex
-)
+// deployRun holds the state shared by the pods deployed in a single create run.
+type deployRun struct {
+	tracker *rollbackTracker
+	journal *journal.Journal
+}
 
 // Create deploys a new application based on a template.
 func (p *PodmanApplication) Create(ctx context.Context, opts types.CreateOptions) error {
@@ -40,6 +47,16 @@ func (p *PodmanApplication) Create(ctx context.Context, opts types.CreateOptions
 		return err
 	}
 
+	// a resumed create reuses the values of the interrupted one, the given values applying on top of them
+	if opts.Resume {
+		valuesFiles, argParams, cleanup, err := withResumedOverrides(opts.Name, opts.ValuesFiles, opts.ArgParams)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		opts.ValuesFiles, opts.ArgParams = valuesFiles, argParams
+	}
+
 	tmpls, err := tp.LoadAllTemplates(opts.TemplateName)
 	if err != nil {
 		return fmt.Errorf("failed to parse the templates: %w", err)
@@ -55,6 +72,38 @@ func (p *PodmanApplication) Create(ctx context.Context, opts types.CreateOptions
 		return fmt.Errorf("failed to verify pod template: %w", err)
 	}
 
+	if err := checkModelCatalog(tp, appMetadata, opts); err != nil {
+		return err
+	}
+
+	// drop the pod templates which are disabled via values
+	tmpls, err = tp.LoadEnabledPodTemplates(opts.TemplateName, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate enabled pod templates: %w", err)
+	}
+
+	if opts.DryRun {
+		return p.dryRun(tp, opts, appMetadata, tmpls)
+	}
+
+	// ---- Deployment Journal ----
+	jrnl, err := p.openJournal(opts)
+	if err != nil {
+		return err
+	}
+
+	// the values are recorded for a resume, as the state of the application is only stored once the create completes
+	if err := recordOverrides(jrnl, opts.ValuesFiles, opts.ArgParams); err != nil {
+		return err
+	}
+
+	// on resume, pods left half-started or unhealthy by the previous run are removed to be deployed again
+	if opts.Resume {
+		if err := p.recheckExistingPods(tp, opts, appMetadata, tmpls, jrnl); err != nil {
+			return err
+		}
+	}
+
 	// Check if pods already exists with the given application name
 	existingPods, err := helpers.CheckExistingPodsForApplication(p.runtime, opts.Name)
 	if err != nil {
@@ -65,70 +114,161 @@ func (p *PodmanApplication) Create(ctx context.Context, opts types.CreateOptions
 	if len(existingPods) == len(tmpls) {
 		logger.Infof("Pods for given app: %s are already deployed. Please use 'ai-services application ps %s' to see the pods deployed\n", opts.Name, opts.Name)
 
-		return nil
+		return jrnl.MarkDone(journal.StepCompleted)
+	}
+
+	// ---- Pre-flight checks on host capacity ----
+	if err := p.runPreflight(tp, opts, tmpls, existingPods); err != nil {
+		return err
 	}
 
 	// ---- Validate Spyre card Requirements ----
-	pciAddresses, err := p.validateAndAllocateSpyreCards(opts.TemplateName, opts.Name, tmpls)
+	placement, err := p.validateAndAllocateSpyreCards(opts, tmpls)
 	if err != nil {
 		return err
 	}
+	defer p.releaseSpyreReservations(opts.Name)
+
+	if err := jrnl.MarkCardsAllocated(placement.Cards()); err != nil {
+		return err
+	}
 
-	if err := p.prepareApplicationArtifacts(ctx, opts); err != nil {
+	if err := p.prepareApplicationArtifacts(ctx, opts, jrnl); err != nil {
 		return err
 	}
 
 	// Loop through all pod templates, render and run kube play
 	logger.Infof("Total Pod Templates to be processed: %d\n", len(tmpls))
 
-	return p.deployApplication(ctx, opts, tmpls, appMetadata, pciAddresses)
+	return p.deployApplication(ctx, opts, tmpls, appMetadata, placement, jrnl)
+}
+
+// openJournal loads the deployment journal of the application, or starts a new one.
+// Resuming requires a journal recorded for the same application template.
+func (p *PodmanApplication) openJournal(opts types.CreateOptions) (*journal.Journal, error) {
+	jrnl, err := journal.Load(opts.Name)
+	if err != nil && !errors.Is(err, journal.ErrNotFound) {
+		return nil, err
+	}
+
+	if opts.Resume {
+		if jrnl == nil {
+			return nil, fmt.Errorf("cannot resume create of application '%s': %w", opts.Name, err)
+		}
+		if jrnl.Template != opts.TemplateName {
+			return nil, fmt.Errorf("cannot resume create of application '%s' created with template '%s' using template '%s'",
+				opts.Name, jrnl.Template, opts.TemplateName)
+		}
+		logger.Infof("Resuming create of application '%s' started at %s\n", opts.Name, jrnl.StartedAt.Format(time.RFC3339))
+
+		return jrnl, nil
+	}
+
+	// keep recording into the journal of the same application, as its existing pods are reused
+	if jrnl != nil && jrnl.Template == opts.TemplateName {
+		return jrnl, nil
+	}
+
+	return journal.New(opts.Name, opts.TemplateName)
 }
 
-func (p *PodmanApplication) validateAndAllocateSpyreCards(templateName, appName string, tmpls map[string]*template.Template) ([]string, error) {
+func (p *PodmanApplication) validateAndAllocateSpyreCards(opts types.CreateOptions, tmpls map[string]*template.Template) (spyre.Placement, error) {
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 
-	reqSpyreCardsCount, err := p.calculateReqSpyreCards(tp, utils.ExtractMapKeys(tmpls), templateName, appName)
+	requests, err := p.calculateReqSpyreCards(tp, utils.ExtractMapKeys(tmpls), opts.TemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculateReqSpyreCards: %w", err)
 	}
 
-	if reqSpyreCardsCount == 0 {
-		return nil, nil
+	if len(requests) == 0 {
+		return spyre.Placement{}, nil
 	}
 
-	// calculate the actual available spyre cards
-	pciAddresses, err := helpers.FindFreeSpyreCards()
+	// reserve the cards in the allocation ledger, so that concurrent deployments never pick the same cards
+	placement, err := spyre.Reserve(p.runtime, opts.Name, requests)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find free Spyre Cards: %w", err)
+		return nil, fmt.Errorf("failed to allocate Spyre Cards: %w", err)
 	}
 
-	actualSpyreCardsCount := len(pciAddresses)
+	return placement, nil
+}
 
-	// validate spyre card requirements
-	if err := p.validateSpyreCardRequirements(reqSpyreCardsCount, actualSpyreCardsCount); err != nil {
-		return nil, err
+// checkModelCatalog checks the models set in the values against the model catalog, before anything is deployed.
+func checkModelCatalog(tp templates.Template, appMetadata *templates.AppMetadata, opts types.CreateOptions) error {
+	values, err := tp.LoadValues(appMetadata.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return fmt.Errorf("failed to load params for application: %w", err)
 	}
 
-	return pciAddresses, nil
+	if _, err := appMetadata.ResolveModels(values); err != nil {
+		return fmt.Errorf("failed to check the models of the application: %w", err)
+	}
+
+	return nil
+}
+
+// releaseSpyreReservations releases the cards reserved by this run which were not assigned to any container.
+func (p *PodmanApplication) releaseSpyreReservations(appName string) {
+	if err := spyre.ReleaseReservations(appName); err != nil {
+		logger.Warningf("failed to release the reserved Spyre cards: %v\n", err)
+	}
 }
 
-func (p *PodmanApplication) prepareApplicationArtifacts(ctx context.Context, opts types.CreateOptions) error {
+func (p *PodmanApplication) prepareApplicationArtifacts(ctx context.Context, opts types.CreateOptions, jrnl *journal.Journal) error {
 	// Download Container Images
-	if err := p.downloadImagesForTemplate(opts.TemplateName, opts.Name, opts.ImagePullPolicy); err != nil {
+	if opts.Resume && jrnl.Done(journal.StepImagesPulled) {
+		logger.Infoln("Images were pulled by the previous run, skipping image download")
+	} else {
+		if err := p.downloadImagesForTemplate(opts); err != nil {
+			return err
+		}
+		if err := jrnl.MarkDone(journal.StepImagesPulled); err != nil {
+			return err
+		}
+	}
+
+	if err := p.prepareModels(ctx, opts, jrnl); err != nil {
 		return err
 	}
 
+	return p.checkModels(opts)
+}
+
+func (p *PodmanApplication) prepareModels(ctx context.Context, opts types.CreateOptions, jrnl *journal.Journal) error {
 	// Download models if flag is set to true(default: true)
-	if !opts.SkipModelDownload {
-		if err := p.downloadModels(ctx, opts.TemplateName, opts.Name); err != nil {
-			return err
-		}
+	if opts.SkipModelDownload {
+		return nil
+	}
+
+	if opts.Resume && jrnl.Done(journal.StepModelsDownloaded) {
+		logger.Infoln("Models were downloaded by the previous run, skipping model download")
+
+		return nil
+	}
+
+	if err := p.downloadModels(ctx, opts); err != nil {
+		return err
+	}
+
+	return jrnl.MarkDone(journal.StepModelsDownloaded)
+}
+
+// checkModels checks that the models mounted by the pods are present and intact, before the pods are deployed.
+func (p *PodmanApplication) checkModels(opts types.CreateOptions) error {
+	models, err := helpers.ListModels(opts.TemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+
+	if err := helpers.CheckModels(models, vars.ModelDirectory); err != nil {
+		return fmt.Errorf("model check failed: %w", err)
 	}
 
 	return nil
 }
 
-func (p *PodmanApplication) deployApplication(ctx context.Context, opts types.CreateOptions, tmpls map[string]*template.Template, appMetadata *templates.AppMetadata, pciAddresses []string) error {
+func (p *PodmanApplication) deployApplication(ctx context.Context, opts types.CreateOptions, tmpls map[string]*template.Template,
+	appMetadata *templates.AppMetadata, placement spyre.Placement, jrnl *journal.Journal) error {
 	logger.Infof("Total Pod Templates to be processed: %d\n", len(tmpls))
 
 	s := spinner.New("Deploying application '" + opts.Name + "'...")
@@ -141,8 +281,23 @@ func (p *PodmanApplication) deployApplication(ctx context.Context, opts types.Cr
 
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 
-	// execute the pod Templates
-	if err := p.executePodTemplates(tp, opts.Name, appMetadata, tmpls, pciAddresses, existingPods, opts.ValuesFiles, opts.ArgParams); err != nil {
+	// execute the pod Templates, recording the pods created by this run
+	run := &deployRun{tracker: &rollbackTracker{}, journal: jrnl}
+	if err := p.executePodTemplates(tp, opts, appMetadata, tmpls, placement, existingPods, run); err != nil {
+		s.Fail("failed to deploy application '" + opts.Name + "'")
+
+		return p.handleDeployFailure(opts, run, err)
+	}
+
+	// the state is stored once the pods are deployed, so that a failed create leaves no state of an application without pods,
+	// and no pods of an application without state, which upgrade, diff, backup and resume depend on
+	if err := saveState(tp, opts.Name, appMetadata, opts.ValuesFiles, opts.ArgParams); err != nil {
+		s.Fail("failed to deploy application '" + opts.Name + "'")
+
+		return p.handleDeployFailure(opts, run, err)
+	}
+
+	if err := jrnl.MarkDone(journal.StepCompleted); err != nil {
 		return err
 	}
 
@@ -161,76 +316,79 @@ func (p *PodmanApplication) deployApplication(ctx context.Context, opts types.Cr
 	return nil
 }
 
-func (p *PodmanApplication) downloadModels(ctx context.Context, templateName, appName string) error {
-	s := spinner.New("Downloading models as part of application creation...")
-	s.Start(ctx)
+// handleDeployFailure rolls back the pods created by this run, unless they are to be kept for debugging.
+func (p *PodmanApplication) handleDeployFailure(opts types.CreateOptions, run *deployRun, deployErr error) error {
+	if opts.KeepOnFailure {
+		logger.Warningf("Keeping the pods created for application '%s' for debugging: %s\n", opts.Name, strings.Join(run.tracker.podNames(), ", "))
+		logger.Infof("Use 'ai-services application delete %s' to remove them, or 'ai-services application create %s -t %s --resume' to continue\n",
+			opts.Name, opts.Name, opts.TemplateName)
+
+		return deployErr
+	}
+
+	if err := p.rollback(opts.Name, run); err != nil {
+		return errors.Join(deployErr, err)
+	}
 
-	models, err := helpers.ListModels(templateName, appName)
+	return deployErr
+}
+
+func (p *PodmanApplication) downloadModels(_ context.Context, opts types.CreateOptions) error {
+	templateName := opts.TemplateName
+	models, err := helpers.ListModels(templateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
 	if err != nil {
-		s.Fail("failed to list models")
+		return fmt.Errorf("failed to list models: %w", err)
+	}
 
-		return err
+	// the pinned models downloaded at another revision are not replaced under the applications using them
+	if err := helpers.CheckDownloadedRevisions(models, vars.ModelDirectory); err != nil {
+		return fmt.Errorf("%w, download the pinned revisions with 'ai-services application model download -t %s'", err, templateName)
 	}
 
 	logger.Infoln("Downloading models required for application template " + templateName + ":")
 
-	for _, model := range models {
-		s.UpdateMessage("Downloading model: " + model + "...")
-		err = utils.Retry(vars.RetryCount, vars.RetryInterval, nil, func() error {
-			return helpers.DownloadModel(model, vars.ModelDirectory)
-		})
-		if err != nil {
-			s.Fail("failed to download model: " + model)
-
-			return fmt.Errorf("failed to download model: %w", err)
-		}
+	if err := helpers.DownloadModels(templateName, models, vars.ModelDirectory); err != nil {
+		return fmt.Errorf("failed to download model: %w", err)
 	}
 
-	s.Stop("Model download completed.")
+	logger.Infoln("Model download completed.")
 
 	return nil
 }
 
 func (p *PodmanApplication) verifyPodTemplateExists(tmpls map[string]*template.Template, appMetadata *templates.AppMetadata) error {
-	flattenPodTemplateExecutions := utils.FlattenArray(appMetadata.PodTemplateExecutions)
+	podTemplates := appMetadata.PodTemplates()
 
-	if len(flattenPodTemplateExecutions) != len(tmpls) {
-		return errors.New("number of values specified in podTemplateExecutions under metadata.yml is mismatched. Please ensure all the pod template file names are specified")
+	if len(podTemplates) != len(tmpls) {
+		return errors.New("number of pod templates specified in podTemplateExecutions and pods under metadata.yml is mismatched. Please ensure all the pod template file names are specified")
 	}
 
-	// Make sure the podTemplateExecution mentioned in metadata.yaml is valid (corresponding pod template is present)
-	for _, podTemplate := range flattenPodTemplateExecutions {
+	// Make sure the pod templates mentioned in metadata.yaml are valid (corresponding pod template is present)
+	for _, podTemplate := range podTemplates {
 		if _, ok := tmpls[podTemplate]; !ok {
-			return fmt.Errorf("value: %s specified in podTemplateExecutions under metadata.yml is invalid. Please ensure corresponding template file exists", podTemplate)
+			return fmt.Errorf("value: %s specified under metadata.yml is invalid. Please ensure corresponding template file exists", podTemplate)
 		}
 	}
 
 	return nil
 }
 
-func (p *PodmanApplication) validateSpyreCardRequirements(req int, actual int) error {
-	if actual < req {
-		return fmt.Errorf("insufficient spyre cards. Require: %d spyre cards to proceed", req)
-	}
-
-	return nil
-}
-
-func (p *PodmanApplication) calculateReqSpyreCards(tp templates.Template, podTemplateFileNames []string, appTemplateName, appName string) (int, error) {
-	totalReqSpyreCounts := 0
+func (p *PodmanApplication) calculateReqSpyreCards(tp templates.Template, podTemplateFileNames []string, appTemplateName, appName string,
+	valuesFiles []string, argParams map[string]string) ([]spyre.Request, error) {
+	var requests []spyre.Request
 
-	// Calculate Req Spyre Counts
+	// Calculate Req Spyre Counts for each container
 	for _, podTemplateFileName := range podTemplateFileNames {
 		// fetch pod spec
-		podSpec, err := p.fetchPodSpec(tp, appTemplateName, podTemplateFileName, appName, nil, nil)
+		podSpec, err := p.fetchPodSpec(tp, appTemplateName, podTemplateFileName, appName, valuesFiles, argParams)
 		if err != nil {
-			return totalReqSpyreCounts, fmt.Errorf("failed to load pod Template: '%s' for appTemplate: '%s' with error: %w", podTemplateFileName, appTemplateName, err)
+			return nil, fmt.Errorf("failed to load pod Template: '%s' for appTemplate: '%s' with error: %w", podTemplateFileName, appTemplateName, err)
 		}
 
 		// check if pod already exists and skip counting if it does exists
 		exists, err := p.runtime.PodExists(podSpec.Name)
 		if err != nil {
-			return totalReqSpyreCounts, fmt.Errorf("failed to check pod status: %w", err)
+			return nil, fmt.Errorf("failed to check pod status: %w", err)
 		}
 
 		if exists {
@@ -239,16 +397,41 @@ func (p *PodmanApplication) calculateReqSpyreCards(tp templates.Template, podTem
 			continue
 		}
 
-		// fetch the spyreCount for all containers from the annotations
-		spyreCount, _, err := p.fetchSpyreCardsFromPodAnnotations(podSpec.Annotations)
+		podRequests, err := p.spyreRequestsForPod(podSpec)
 		if err != nil {
-			return totalReqSpyreCounts, err
+			return nil, err
 		}
+		requests = append(requests, podRequests...)
+	}
+
+	return requests, nil
+}
 
-		totalReqSpyreCounts += spyreCount
+// spyreRequestsForPod returns the Spyre cards requested by each container of the pod.
+func (p *PodmanApplication) spyreRequestsForPod(podSpec *models.PodSpec) ([]spyre.Request, error) {
+	// fetch the spyreCount for all containers from the annotations
+	_, spyreCardContainerMap, err := p.fetchSpyreCardsFromPodAnnotations(podSpec.Annotations)
+	if err != nil {
+		return nil, err
 	}
 
-	return totalReqSpyreCounts, nil
+	pinnedCards := p.fetchPinnedSpyreCardsFromPodAnnotations(podSpec.Annotations)
+
+	var requests []spyre.Request
+	for _, containerName := range slices.Sorted(maps.Keys(spyreCardContainerMap)) {
+		if spyreCardContainerMap[containerName] == 0 {
+			continue
+		}
+
+		requests = append(requests, spyre.Request{
+			Pod:       podSpec.Name,
+			Container: containerName,
+			Count:     spyreCardContainerMap[containerName],
+			Pinned:    pinnedCards[containerName],
+		})
+	}
+
+	return requests, nil
 }
 
 func (p *PodmanApplication) fetchPodSpec(tp templates.Template, appTemplateName, podTemplateFileName, appName string, valuesFiles []string, argParams map[string]string) (*models.PodSpec, error) {
@@ -289,78 +472,76 @@ func (p *PodmanApplication) fetchSpyreCardsFromPodAnnotations(annotations map[st
 	return spyreCards, spyreCardContainerMap, nil
 }
 
-func (p *PodmanApplication) downloadImagesForTemplate(templateName, appName string, imagePullPolicy image.ImagePullPolicy) error {
+// fetchPinnedSpyreCardsFromPodAnnotations returns the PCI addresses of the Spyre cards pinned for each container
+// via the ai-services.io/<containerName>--spyre-pci-addresses annotation, as a whitespace or comma separated list.
+func (p *PodmanApplication) fetchPinnedSpyreCardsFromPodAnnotations(annotations map[string]string) map[string][]string {
+	pinnedCards := map[string][]string{}
+
+	for annotationKey, val := range annotations {
+		matches := vars.SpyrePCIAddressesAnnotationRegex.FindStringSubmatch(annotationKey)
+		if matches == nil {
+			continue
+		}
+
+		pinnedCards[matches[1]] = strings.Fields(strings.ReplaceAll(val, ",", " "))
+	}
+
+	return pinnedCards
+}
+
+func (p *PodmanApplication) downloadImagesForTemplate(opts types.CreateOptions) error {
 	// create Images struct and run with the specified policy
 	img := &image.Images{
 		Runtime:     p.runtime,
-		App:         appName,
-		AppTemplate: templateName,
+		App:         opts.Name,
+		AppTemplate: opts.TemplateName,
+		ValuesFiles: opts.ValuesFiles,
+		ArgParams:   opts.ArgParams,
+		Verify:      opts.VerifyImages,
 	}
 
-	return img.Run(imagePullPolicy)
+	return img.Run(opts.ImagePullPolicy)
 }
 
-func (p *PodmanApplication) executePodTemplates(tp templates.Template,
-	appName string, appMetadata *templates.AppMetadata,
-	tmpls map[string]*template.Template, pciAddresses []string, existingPods []string,
-	valuesFiles []string, argParams map[string]string) error {
+func (p *PodmanApplication) executePodTemplates(tp templates.Template, opts types.CreateOptions, appMetadata *templates.AppMetadata,
+	tmpls map[string]*template.Template, placement spyre.Placement, existingPods []string, run *deployRun) error {
+	appName := opts.Name
+
 	// Load values for template rendering
-	values, err := tp.LoadValues(appMetadata.Name, valuesFiles, argParams)
+	values, err := tp.LoadValues(appMetadata.Name, opts.ValuesFiles, opts.ArgParams)
 	if err != nil {
 		return fmt.Errorf("failed to load params for application: %w", err)
 	}
 
-	globalParams := map[string]any{
-		"AppName":         appName,
-		"AppTemplateName": appMetadata.Name,
-		"Version":         appMetadata.Version,
-		"Values":          values,
-		// Key -> container name
-		// Value -> range of key-value env pairs
-		"env": map[string]map[string]string{},
+	globalParams, err := globalTemplateParams(appName, appMetadata, values)
+	if err != nil {
+		return err
 	}
 
-	// looping over each layer of podTemplateExecutions
-	for i, layer := range appMetadata.PodTemplateExecutions {
-		logger.Infof("\n Executing Layer %d/%d: %v\n", i+1, len(appMetadata.PodTemplateExecutions), layer)
-		logger.Infoln("-------")
-		var wg sync.WaitGroup
-		errCh := make(chan error, len(layer))
-
-		// for each layer, fetch all the pod Template Names and do the pod deploy
-		for _, podTemplateName := range layer {
-			wg.Add(1)
-			go func(t string) {
-				defer wg.Done()
-				if err := p.executePodTemplateLayer(tp, tmpls, globalParams, pciAddresses, existingPods, podTemplateName, appName, valuesFiles, argParams); err != nil {
-					errCh <- err
-				}
-			}(podTemplateName)
-		}
-
-		wg.Wait()
-		close(errCh)
-
-		// collect all errors for this layer
-		var errs []error
-		for e := range errCh {
-			errs = append(errs, fmt.Errorf("layer %d: %w", i+1, e))
-		}
-
-		// If an error exist for a given layer, then return (do not process further layers)
-		if len(errs) > 0 {
-			return errors.Join(errs...)
-		}
+	// build the dependency graph of the enabled pod templates
+	graph, err := appMetadata.BuildPodGraph(values)
+	if err != nil {
+		return fmt.Errorf("failed to build pod dependency graph: %w", err)
+	}
 
-		logger.Infof("Layer %d completed\n", i+1)
+	maxParallelism := opts.MaxParallelism
+	if maxParallelism <= 0 {
+		maxParallelism = appMetadata.MaxParallelism
 	}
 
-	return nil
+	logger.Infof("\n Deploying %d pod templates (max parallelism: %d)\n", len(graph.Order), maxParallelism)
+	logger.Infoln("-------")
+
+	// placement is only read by the pods deployed in parallel, each pod picking its own cards
+	return clipodman.DeployPodGraph(graph, maxParallelism, func(podTemplateName string) error {
+		return p.executePodTemplate(tp, tmpls, globalParams, placement, existingPods, podTemplateName, appName,
+			opts.ValuesFiles, opts.ArgParams, appMetadata.PodTimeouts(podTemplateName), run)
+	})
 }
 
-func (p *PodmanApplication) executePodTemplateLayer(tp templates.Template, tmpls map[string]*template.Template,
-	globalParams map[string]any, pciAddresses []string, existingPods []string, podTemplateName, appName string,
-	valuesFiles []string, argParams map[string]string) error {
+func (p *PodmanApplication) executePodTemplate(tp templates.Template, tmpls map[string]*template.Template,
+	globalParams map[string]any, placement spyre.Placement, existingPods []string, podTemplateName, appName string,
+	valuesFiles []string, argParams map[string]string, timeouts templates.Timeouts, run *deployRun) error {
 	logger.Infof("'%s': Processing template...\n", podTemplateName)
 
 	// Shallow Copy globalParams Map
@@ -382,35 +563,93 @@ func (p *PodmanApplication) executePodTemplateLayer(tp templates.Template, tmpls
 	podAnnotations := p.fetchPodAnnotations(podSpec)
 
 	// get the env params for a given pod
-	env, err := p.returnEnvParamsForPod(podSpec, podAnnotations, &pciAddresses)
+	env, err := p.returnEnvParamsForPod(podSpec, podAnnotations, placement)
 	if err != nil {
 		return fmt.Errorf("'%s': Failed to fetch env params: %w", podTemplateName, err)
 	}
 	params["env"] = env
 
-	podTemplate := tmpls[podTemplateName]
+	timeouts, err = clipodman.ResolvePodTimeouts(podSpec, timeouts)
+	if err != nil {
+		return fmt.Errorf("'%s': %w", podTemplateName, err)
+	}
+
+	// record the pod before creating it, so that it is rolled back even if it fails midway
+	spyreCards := spyreCardsFromEnv(env)
+	run.tracker.record(podSpec.Name, slices.Concat(slices.Collect(maps.Values(spyreCards))...))
 
-	var rendered bytes.Buffer
-	if err := podTemplate.Execute(&rendered, params); err != nil {
+	rendered, err := renderPodTemplate(tmpls[podTemplateName], params)
+	if err != nil {
 		return fmt.Errorf("'%s': Failed to parse pod template: %w", podTemplateName, err)
 	}
 
 	// Wrap the bytes in a bytes.Reader
-	reader := bytes.NewReader(rendered.Bytes())
+	reader := bytes.NewReader(rendered)
 
-	// Deploy the Pod and do Readiness check
-	if err := clipodman.DeployPodAndReadinessCheck(p.runtime, podSpec, podTemplateName, reader, clipodman.ConstructPodDeployOptions(podAnnotations)); err != nil {
+	// Deploy the Pod and do Readiness check, journaling each of the steps
+	pods, err := clipodman.DeployPod(p.runtime, podTemplateName, reader, clipodman.ConstructPodDeployOptions(podAnnotations))
+	if err != nil {
 		return fmt.Errorf("'%s': Failed to deploy pod and do readiness check: %w", podTemplateName, err)
 	}
 
-	return nil
+	if err := run.journal.MarkPodPlayed(podTemplateName, podSpec.Name, spyreCards); err != nil {
+		return err
+	}
+
+	if err := spyre.Assign(appName, podSpec.Name, spyreCards); err != nil {
+		return fmt.Errorf("'%s': Failed to record the allocated Spyre cards: %w", podTemplateName, err)
+	}
+
+	// store the spec the pod is deployed with, for upgrade to find out whether it changed
+	if err := state.SavePodSpec(appName, podTemplateName, rendered); err != nil {
+		return fmt.Errorf("'%s': %w", podTemplateName, err)
+	}
+
+	if err := clipodman.PodReadinessCheck(p.runtime, podSpec, podTemplateName, pods, timeouts); err != nil {
+		return fmt.Errorf("'%s': Failed to deploy pod and do readiness check: %w", podTemplateName, err)
+	}
+
+	return run.journal.MarkPodReady(podTemplateName)
+}
+
+// globalTemplateParams returns the params shared by all the pod templates of the application.
+func globalTemplateParams(appName string, appMetadata *templates.AppMetadata, values map[string]any) (map[string]any, error) {
+	resolvedModels, err := appMetadata.ResolveModels(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"AppName":         appName,
+		"AppTemplateName": appMetadata.Name,
+		"Version":         appMetadata.Version,
+		"Values":          values,
+		// Key -> model role, Value -> model checked against the model catalog
+		"Models": resolvedModels,
+		// Key -> container name
+		// Value -> range of key-value env pairs
+		"env": map[string]map[string]string{},
+	}, nil
+}
+
+// renderPodTemplate renders the pod template into the kube YAML passed to kube play,
+// the image references rewritten to the configured registry mirrors and pinned to their digests.
+func renderPodTemplate(podTemplate *template.Template, params map[string]any) ([]byte, error) {
+	var rendered bytes.Buffer
+	if err := podTemplate.Execute(&rendered, params); err != nil {
+		return nil, err
+	}
+
+	templateName, _ := params["AppTemplateName"].(string)
+
+	return image.ResolveManifest(templateName, rendered.Bytes())
 }
 
 func (p *PodmanApplication) fetchPodAnnotations(podSpec *models.PodSpec) map[string]string {
 	return specs.FetchPodAnnotations(*podSpec)
 }
 
-func (p *PodmanApplication) returnEnvParamsForPod(podSpec *models.PodSpec, podAnnotations map[string]string, pciAddresses *[]string) (map[string]map[string]string, error) {
+func (p *PodmanApplication) returnEnvParamsForPod(podSpec *models.PodSpec, podAnnotations map[string]string, placement spyre.Placement) (map[string]map[string]string, error) {
 	env := map[string]map[string]string{}
 	podContainerNames := specs.FetchContainerNames(*podSpec)
 
@@ -430,15 +669,30 @@ func (p *PodmanApplication) returnEnvParamsForPod(podSpec *models.PodSpec, podAn
 		return env, nil
 	}
 
-	// Construct env for a given pod
-	// Since this is a critical section as both requires pciAddresses and modifies -> wrap it in mutex
-	envMutex.Lock()
+	// Construct env for a given pod, with the cards placed for each of its containers
 	for container, spyreCount := range spyreCardContainerMap {
-		if spyreCount != 0 {
-			env[container] = map[string]string{string(constants.PCIAddressKey): utils.JoinAndRemove(pciAddresses, spyreCount, " ")}
+		if spyreCount == 0 {
+			continue
+		}
+
+		cards := placement[podSpec.Name][container]
+		if len(cards) != spyreCount {
+			return env, fmt.Errorf("%d spyre cards are allocated to container '%s', %d required", len(cards), container, spyreCount)
 		}
+		env[container] = map[string]string{string(constants.PCIAddressKey): strings.Join(cards, " ")}
 	}
-	envMutex.Unlock()
 
 	return env, nil
 }
+
+// spyreCardsFromEnv returns the PCI addresses of the Spyre cards assigned to each container of a pod.
+func spyreCardsFromEnv(env map[string]map[string]string) map[string][]string {
+	cards := map[string][]string{}
+	for container, containerEnv := range env {
+		if addresses := strings.Fields(containerEnv[string(constants.PCIAddressKey)]); len(addresses) > 0 {
+			cards[container] = addresses
+		}
+	}
+
+	return cards
+}
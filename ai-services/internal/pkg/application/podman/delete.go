@@ -11,6 +11,7 @@ import (
 	"github.com/project-ai-services/ai-services/internal/pkg/constants"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
 	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 )
 
@@ -50,7 +51,7 @@ func (p *PodmanApplication) Delete(_ context.Context, opts appTypes.DeleteOption
 
 	logger.Infoln("Proceeding with deletion...")
 
-	if err := p.podsDeletion(pods); err != nil {
+	if err := p.podsDeletion(opts.Name, pods); err != nil {
 		return err
 	}
 
@@ -93,8 +94,9 @@ func (p *PodmanApplication) deleteConfirmation(appName string, podsExists, appEx
 	return confirmDelete, nil
 }
 
-func (p *PodmanApplication) podsDeletion(pods []types.Pod) error {
+func (p *PodmanApplication) podsDeletion(appName string, pods []types.Pod) error {
 	var errors []string
+	var deleted []string
 
 	for _, pod := range pods {
 		logger.Infof("Deleting pod: %s\n", pod.Name)
@@ -106,6 +108,12 @@ func (p *PodmanApplication) podsDeletion(pods []types.Pod) error {
 		}
 
 		logger.Infof("Successfully removed pod: %s\n", pod.Name)
+		deleted = append(deleted, pod.Name)
+	}
+
+	// release the Spyre cards held by the removed pods
+	if err := spyre.ReleasePods(appName, deleted); err != nil {
+		errors = append(errors, fmt.Sprintf("failed to release the Spyre cards: %v", err))
 	}
 
 	// Aggregate errors at the end
@@ -0,0 +1,194 @@
+package podman
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "github.com/containers/podman/v5/pkg/k8s.io/api/core/v1"
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/models"
+	runtimeTypes "github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+)
+
+const (
+	driftMissing = "<missing>"
+	driftNotSet  = "<not set>"
+)
+
+// Diff renders the pod templates with the values the application is deployed with, the way upgrade does,
+// and compares the rendered pods with the running ones: images, env, ports, annotations and resource limits.
+// The env values of the secrets are redacted.
+func (p *PodmanApplication) Diff(_ context.Context, opts types.DiffOptions) ([]types.Drift, error) {
+	templateName, pods, err := p.deployedApplication(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
+
+	valuesFiles, argParams, cleanup, err := withStoredOverrides(opts.Name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	appMetadata, tmpls, err := p.loadEnabledTemplates(tp, templateName, valuesFiles, argParams)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradeOpts := types.UpgradeOptions{Name: opts.Name, ValuesFiles: valuesFiles, ArgParams: argParams, DryRun: true}
+	upgrades, removed, err := p.planUpgrade(tp, upgradeOpts, templateName, appMetadata, tmpls, pods)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []types.Drift
+	for _, u := range upgrades {
+		if u.action == upgradeCreate {
+			drifts = append(drifts, types.Drift{Resource: u.podSpec.Name, Field: "pod", Expected: "present", Actual: driftMissing})
+
+			continue
+		}
+
+		podDrifts, err := p.podDrift(u)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, podDrifts...)
+	}
+
+	for _, podName := range removed {
+		drifts = append(drifts, types.Drift{Resource: podName, Field: "pod", Expected: "not part of the template", Actual: "present"})
+	}
+
+	return drifts, nil
+}
+
+// podDrift compares the rendered spec of the pod with the running pod and its containers.
+func (p *PodmanApplication) podDrift(u *podUpgrade) ([]types.Drift, error) {
+	var expected models.PodSpec
+	if err := k8syaml.Unmarshal(u.rendered, &expected); err != nil {
+		return nil, fmt.Errorf("'%s': unable to read the rendered spec: %w", u.template, err)
+	}
+
+	pod, err := p.runtime.InspectPod(expected.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect pod %s: %w", expected.Name, err)
+	}
+
+	var drifts []types.Drift
+	for _, c := range expected.Spec.Containers {
+		drift := func(field, expected, actual string) {
+			drifts = append(drifts, types.Drift{Resource: pod.Name, Container: c.Name, Field: field, Expected: expected, Actual: actual})
+		}
+
+		idx := slices.IndexFunc(pod.Containers, func(container runtimeTypes.Container) bool {
+			return container.Name == pod.Name+"-"+c.Name
+		})
+		if idx < 0 {
+			drift("container", "present", driftMissing)
+
+			continue
+		}
+
+		cInfo, err := p.runtime.InspectContainer(pod.Containers[idx].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container %s: %w", pod.Containers[idx].Name, err)
+		}
+
+		compareContainer(c, cInfo, expected.Annotations, pod.Ports, drift)
+	}
+
+	return drifts, nil
+}
+
+// compareContainer reports the fields of the running container differing from its spec. Only the env and the
+// annotations set in the spec are compared, as the running container also holds the ones set by the image and podman.
+func compareContainer(c v1.Container, cInfo *runtimeTypes.Container, annotations map[string]string,
+	ports map[string][]string, drift func(field, expected, actual string)) {
+	if c.Image != cInfo.Image {
+		drift("image", c.Image, cInfo.Image)
+	}
+
+	for _, env := range c.Env {
+		if env.ValueFrom != nil {
+			continue
+		}
+
+		actual, ok := cInfo.Env[env.Name]
+		switch {
+		case !ok:
+			drift("env."+env.Name, redactEnv(env.Name, env.Value), driftMissing)
+		case actual != env.Value:
+			drift("env."+env.Name, redactEnv(env.Name, env.Value), redactEnv(env.Name, actual))
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		actual, ok := cInfo.Annotations[key]
+		switch {
+		case !ok:
+			drift("annotation."+key, annotations[key], driftMissing)
+		case actual != annotations[key]:
+			drift("annotation."+key, annotations[key], actual)
+		}
+	}
+
+	for _, port := range c.Ports {
+		if port.HostPort == 0 {
+			continue
+		}
+
+		protocol := strings.ToLower(string(port.Protocol))
+		if protocol == "" {
+			protocol = "tcp"
+		}
+
+		key := fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+		hostPort := strconv.Itoa(int(port.HostPort))
+		if !slices.Contains(ports[key], hostPort) {
+			drift("port."+key, hostPort, strings.Join(ports[key], ","))
+		}
+	}
+
+	if memory := c.Resources.Limits.Memory(); !memory.IsZero() && memory.Value() != cInfo.MemoryLimit {
+		drift("limits.memory", memory.String(), formatLimit(cInfo.MemoryLimit, strconv.FormatInt(cInfo.MemoryLimit, 10)))
+	}
+
+	// CPU limits are compared in millicores, the precision kube play applies them with
+	if cpu := c.Resources.Limits.Cpu(); !cpu.IsZero() && cpu.MilliValue() != nanoCPUsToMilli(cInfo.NanoCPUs) {
+		drift("limits.cpu", cpu.String(), formatLimit(cInfo.NanoCPUs, fmt.Sprintf("%dm", nanoCPUsToMilli(cInfo.NanoCPUs))))
+	}
+}
+
+func nanoCPUsToMilli(nanoCPUs int64) int64 {
+	const nanoPerMilli = 1_000_000
+
+	return nanoCPUs / nanoPerMilli
+}
+
+func formatLimit(limit int64, formatted string) string {
+	if limit == 0 {
+		return driftNotSet
+	}
+
+	return formatted
+}
+
+func redactEnv(name, value string) string {
+	if state.IsSecretKey(name) && value != "" {
+		return state.Redacted
+	}
+
+	return value
+}
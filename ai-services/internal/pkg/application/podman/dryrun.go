@@ -0,0 +1,147 @@
+package podman
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// dryRun runs the create pipeline without any side effect: the values are merged and the pod templates
+// are rendered with their env and a simulated Spyre card allocation. The deployment plan is logged, and the
+// kube YAML that kube play would receive is printed to stdout, so that it can be reviewed or committed.
+func (p *PodmanApplication) dryRun(tp templates.Template, opts types.CreateOptions, appMetadata *templates.AppMetadata,
+	tmpls map[string]*template.Template) error {
+	logger.Infof("Dry run: nothing will be pulled, downloaded or deployed for application '%s'\n", opts.Name)
+
+	values, err := tp.LoadValues(appMetadata.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return fmt.Errorf("failed to load params for application: %w", err)
+	}
+
+	graph, err := appMetadata.BuildPodGraph(values)
+	if err != nil {
+		return fmt.Errorf("failed to build pod dependency graph: %w", err)
+	}
+
+	// simulate the Spyre card allocation for all the pods, as if none was deployed yet
+	var requests []spyre.Request
+	for _, podTemplateName := range graph.Order {
+		podSpec, err := p.fetchPodSpec(tp, opts.TemplateName, podTemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+		if err != nil {
+			return err
+		}
+
+		podRequests, err := p.spyreRequestsForPod(podSpec)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, podRequests...)
+	}
+
+	placement, err := spyre.Simulate(requests)
+	if err != nil {
+		return fmt.Errorf("failed to simulate Spyre cards allocation: %w", err)
+	}
+
+	globalParams, err := globalTemplateParams(opts.Name, appMetadata, values)
+	if err != nil {
+		return err
+	}
+
+	var manifests strings.Builder
+	for _, podTemplateName := range graph.Order {
+		podSpec, err := p.fetchPodSpec(tp, opts.TemplateName, podTemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+		if err != nil {
+			return err
+		}
+
+		env, err := p.returnEnvParamsForPod(podSpec, p.fetchPodAnnotations(podSpec), placement)
+		if err != nil {
+			return fmt.Errorf("'%s': Failed to fetch env params: %w", podTemplateName, err)
+		}
+
+		params := utils.CopyMap(globalParams)
+		params["env"] = env
+
+		rendered, err := renderPodTemplate(tmpls[podTemplateName], params)
+		if err != nil {
+			return fmt.Errorf("'%s': Failed to parse pod template: %w", podTemplateName, err)
+		}
+
+		fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", podTemplateName, strings.TrimSpace(string(rendered)))
+	}
+
+	if err := p.printDeploymentPlan(opts, graph, placement); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(os.Stdout, manifests.String()); err != nil {
+		return fmt.Errorf("failed to print the rendered pod specs: %w", err)
+	}
+
+	return nil
+}
+
+// printDeploymentPlan logs the images to be pulled, the models to be downloaded, the layers
+// the pods are deployed in and the simulated Spyre card allocation.
+func (p *PodmanApplication) printDeploymentPlan(opts types.CreateOptions, graph *templates.PodGraph, placement spyre.Placement) error {
+	img := &image.Images{
+		App:         opts.Name,
+		AppTemplate: opts.TemplateName,
+		ValuesFiles: opts.ValuesFiles,
+		ArgParams:   opts.ArgParams,
+	}
+	images, err := img.ListImages()
+	if err != nil {
+		return fmt.Errorf("error listing images: %w", err)
+	}
+
+	modelList, err := helpers.ListModels(opts.TemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return err
+	}
+
+	logger.Infoln("Images:")
+	for _, imageRef := range images {
+		logger.Infof("\t-> %s\n", imageRef)
+	}
+
+	logger.Infoln("Models:")
+	for _, model := range modelList {
+		logger.Infof("\t-> %s\n", model)
+	}
+
+	logger.Infoln("Deployment layers:")
+	for i, layer := range graph.Layers() {
+		logger.Infof("\t%d: %s\n", i+1, strings.Join(layer, ", "))
+	}
+
+	if len(placement) == 0 {
+		return nil
+	}
+
+	logger.Infoln("Spyre cards allocation (simulated):")
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("POD", "CONTAINER", "PCI ADDRESSES")
+	for _, pod := range slices.Sorted(maps.Keys(placement)) {
+		for _, container := range slices.Sorted(maps.Keys(placement[pod])) {
+			printer.AppendRow(pod, container, strings.Join(placement[pod][container], " "))
+		}
+	}
+
+	return nil
+}
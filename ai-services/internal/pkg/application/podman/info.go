@@ -2,8 +2,11 @@ package podman
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/common"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
 	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
@@ -42,6 +45,23 @@ func (p *PodmanApplication) Info(opts types.InfoOptions) error {
 	version := pods[0].Labels[string(vars.VersionLabel)]
 	logger.Infoln("Version: " + version)
 
+	if m, err := state.Load(opts.Name); err == nil {
+		logger.Infoln("CLI Version: " + m.CLIVersion)
+		logger.Infoln("Created: " + m.CreatedAt.Format(time.RFC3339))
+		logger.Infoln("Updated: " + m.UpdatedAt.Format(time.RFC3339))
+	}
+
+	if opts.ShowValues {
+		values, err := p.GetValues(types.GetValuesOptions{Name: opts.Name, All: true})
+		if err != nil {
+			return err
+		}
+
+		if err := common.PrintValues(values); err != nil {
+			return err
+		}
+	}
+
 	// Step3: Read and print the info.md file
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 
@@ -0,0 +1,89 @@
+package podman
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/preflight"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/models"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+// runPreflight checks the host capacity against the pods yet to be deployed and the models yet to be downloaded.
+// Failed checks abort the create, unless it is forced.
+func (p *PodmanApplication) runPreflight(tp templates.Template, opts types.CreateOptions, tmpls map[string]*template.Template,
+	existingPods []string) error {
+	var pods []*models.PodSpec
+	for podTemplateName := range tmpls {
+		podSpec, err := p.fetchPodSpec(tp, opts.TemplateName, podTemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(existingPods, podSpec.Name) {
+			continue
+		}
+		pods = append(pods, podSpec)
+	}
+
+	input := preflight.Input{Pods: pods, ModelDirectory: vars.ModelDirectory}
+	if !opts.SkipModelDownload {
+		if err := withModelSizes(&input, opts); err != nil {
+			return err
+		}
+	}
+
+	results := preflight.Run(input)
+	failed := preflight.Failed(results)
+
+	if len(failed) == 0 {
+		logger.Infoln("Pre-flight checks on host capacity passed", logger.VerbosityLevelDebug)
+
+		return nil
+	}
+
+	logger.Infoln("Pre-flight checks on host capacity:")
+	preflight.Print(results)
+
+	checks := make([]string, 0, len(failed))
+	for _, result := range failed {
+		checks = append(checks, result.Check)
+	}
+
+	if opts.Force {
+		logger.Warningf("Pre-flight checks failed (%s), proceeding as --force is set\n", strings.Join(checks, ", "))
+
+		return nil
+	}
+
+	return fmt.Errorf("pre-flight checks failed: %s. Free up the host resources, or use --force to deploy anyway", strings.Join(checks, ", "))
+}
+
+// withModelSizes sets the models of the application on the input, their sizes looked up in the sources they are
+// downloaded from, so that the models of internal storages are never looked up on the Hugging Face hub.
+func withModelSizes(input *preflight.Input, opts types.CreateOptions) error {
+	specs, err := helpers.ListModels(opts.TemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return err
+	}
+
+	sources, err := helpers.ModelSources(opts.TemplateName, specs)
+	if err != nil {
+		return err
+	}
+
+	input.Models = slices.Sorted(maps.Keys(sources))
+	input.ModelSize = func(ctx context.Context, model string) (int64, error) {
+		return sources[model].Size(ctx, model)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package podman
+
+import (
+	"fmt"
+	"slices"
+	"text/template"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/journal"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	clipodman "github.com/project-ai-services/ai-services/internal/pkg/cli/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/models"
+	runtimeTypes "github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/specs"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// container states for which a pod is not considered healthy.
+var stoppedContainerStates = []string{"created", "exited", "stopped", "paused"}
+
+// recheckExistingPods goes through the pods left by the previous run: the pods which are healthy are
+// journaled as ready and kept, whereas the half-started or unhealthy ones are removed, so that they are
+// deployed again along with the Spyre cards they held.
+func (p *PodmanApplication) recheckExistingPods(tp templates.Template, opts types.CreateOptions,
+	appMetadata *templates.AppMetadata, tmpls map[string]*template.Template, jrnl *journal.Journal) error {
+	logger.Infoln("Re-checking the pods deployed by the previous run...")
+
+	for _, podTemplateName := range appMetadata.PodTemplates() {
+		if _, ok := tmpls[podTemplateName]; !ok {
+			// the pod template is disabled
+			continue
+		}
+
+		podSpec, err := p.fetchPodSpec(tp, opts.TemplateName, podTemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+		if err != nil {
+			return err
+		}
+
+		exists, err := p.runtime.PodExists(podSpec.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check pod status: %w", err)
+		}
+
+		if !exists {
+			continue
+		}
+
+		healthy, reason := p.checkExistingPod(podSpec, podTemplateName, appMetadata.PodTimeouts(podTemplateName))
+		if healthy {
+			if err := p.journalExistingPod(jrnl, podTemplateName, podSpec.Name); err != nil {
+				return err
+			}
+			logger.Infof("'%s': Pod '%s' is healthy, keeping it\n", podTemplateName, podSpec.Name)
+
+			continue
+		}
+
+		logger.Warningf("'%s': Pod '%s' is not healthy (%s), removing it to deploy it again\n", podTemplateName, podSpec.Name, reason)
+		if err := p.runtime.DeletePod(podSpec.Name, utils.BoolPtr(true)); err != nil {
+			return fmt.Errorf("failed to remove unhealthy pod '%s': %w", podSpec.Name, err)
+		}
+
+		if err := spyre.ReleasePods(opts.Name, []string{podSpec.Name}); err != nil {
+			return fmt.Errorf("failed to release the Spyre cards of pod '%s': %w", podSpec.Name, err)
+		}
+
+		if err := jrnl.ForgetPod(podSpec.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkExistingPod reports whether the existing pod is healthy, along with the reason if it is not.
+func (p *PodmanApplication) checkExistingPod(podSpec *models.PodSpec, podTemplateName string, timeouts templates.Timeouts) (bool, string) {
+	pInfo, err := p.runtime.InspectPod(podSpec.Name)
+	if err != nil {
+		return false, fmt.Sprintf("failed to inspect pod: %v", err)
+	}
+
+	// pods which are not started on deploy are healthy as long as all their containers are created
+	startOff := specs.FetchPodAnnotations(*podSpec)[constants.PodStartAnnotationkey] == constants.PodStartOff
+
+	// Note: there is an additional 'infra' container added to all pods by podman
+	if len(pInfo.Containers) < len(podSpec.Spec.Containers)+1 {
+		return false, "not all containers are created"
+	}
+
+	for _, container := range pInfo.Containers {
+		if container.ID == pInfo.InfraContainerID || startOff {
+			continue
+		}
+
+		cInfo, err := p.runtime.InspectContainer(container.ID)
+		if err != nil {
+			return false, fmt.Sprintf("failed to inspect container: %v", err)
+		}
+
+		if slices.Contains(stoppedContainerStates, cInfo.Status) {
+			return false, fmt.Sprintf("container '%s' is %s", cInfo.Name, cInfo.Status)
+		}
+	}
+
+	if startOff {
+		return true, ""
+	}
+
+	// containers still starting up are given the chance to become ready
+	timeouts, err = clipodman.ResolvePodTimeouts(podSpec, timeouts)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if err := clipodman.PodReadinessCheck(p.runtime, podSpec, podTemplateName, []runtimeTypes.Pod{*pInfo}, timeouts); err != nil {
+		return false, err.Error()
+	}
+
+	return true, ""
+}
+
+// journalExistingPod records a healthy existing pod as played and ready, if the journal does not already.
+func (p *PodmanApplication) journalExistingPod(jrnl *journal.Journal, podTemplateName, podName string) error {
+	pod := jrnl.Pod(podTemplateName)
+	if pod != nil && pod.Name == podName && !pod.ReadyAt.IsZero() {
+		return nil
+	}
+
+	if pod == nil || pod.Name != podName {
+		if err := jrnl.MarkPodPlayed(podTemplateName, podName, nil); err != nil {
+			return err
+		}
+	}
+
+	return jrnl.MarkPodReady(podTemplateName)
+}
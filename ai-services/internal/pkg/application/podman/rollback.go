@@ -0,0 +1,138 @@
+package podman
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// createdPod is a pod created by the current create run.
+type createdPod struct {
+	name string
+	// spyreCards are the PCI addresses of the Spyre cards assigned to the containers of the pod
+	spyreCards []string
+}
+
+// rollbackTracker records the pods created by the current create run, so that only those
+// are removed if the run fails. Pods which already existed before the run are never recorded.
+type rollbackTracker struct {
+	mu   sync.Mutex
+	pods []createdPod
+}
+
+// record registers a pod about to be created, along with the Spyre cards assigned to it.
+func (t *rollbackTracker) record(podName string, spyreCards []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pods = append(t.pods, createdPod{name: podName, spyreCards: spyreCards})
+}
+
+// podNames returns the names of the recorded pods, in the order they were created.
+func (t *rollbackTracker) podNames() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.pods))
+	for _, pod := range t.pods {
+		names = append(names, pod.name)
+	}
+
+	return names
+}
+
+// rollback removes the pods created by the current run in the reverse order of their creation
+// and prints a summary of the removed pods and the freed Spyre cards.
+func (p *PodmanApplication) rollback(appName string, run *deployRun) error {
+	run.tracker.mu.Lock()
+	pods := slices.Clone(run.tracker.pods)
+	run.tracker.mu.Unlock()
+
+	if len(pods) == 0 {
+		logger.Infof("No pods were created for application '%s', nothing to roll back\n", appName)
+
+		return nil
+	}
+
+	logger.Infof("Rolling back %d pods created for application '%s'...\n", len(pods), appName)
+
+	var errs []string
+	status := make(map[string]string, len(pods))
+
+	slices.Reverse(pods)
+	for _, pod := range pods {
+		// the pod might not exist if its creation failed
+		exists, err := p.runtime.PodExists(pod.name)
+		if err != nil {
+			status[pod.name] = "Failed"
+			errs = append(errs, fmt.Sprintf("pod %s: %v", pod.name, err))
+
+			continue
+		}
+
+		if !exists {
+			status[pod.name] = "Not created"
+			p.forgetJournaledPod(run, pod.name)
+
+			continue
+		}
+
+		logger.Infof("Deleting pod: %s\n", pod.name, logger.VerbosityLevelDebug)
+		if err := p.runtime.DeletePod(pod.name, utils.BoolPtr(true)); err != nil {
+			status[pod.name] = "Failed"
+			errs = append(errs, fmt.Sprintf("pod %s: %v", pod.name, err))
+
+			continue
+		}
+		status[pod.name] = "Removed"
+		p.forgetJournaledPod(run, pod.name)
+	}
+
+	var released []string
+	for _, pod := range pods {
+		if status[pod.name] != "Failed" {
+			released = append(released, pod.name)
+		}
+	}
+
+	if err := spyre.ReleasePods(appName, released); err != nil {
+		errs = append(errs, fmt.Sprintf("failed to release the Spyre cards: %v", err))
+	}
+
+	printRollbackSummary(pods, status)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to roll back pods: \n%s", strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+// forgetJournaledPod drops the removed pod from the journal, so that a resumed create deploys it again.
+func (p *PodmanApplication) forgetJournaledPod(run *deployRun, podName string) {
+	if err := run.journal.ForgetPod(podName); err != nil {
+		logger.Warningf("failed to update the deployment journal: %v\n", err)
+	}
+}
+
+func printRollbackSummary(pods []createdPod, status map[string]string) {
+	logger.Infoln("Rollback summary:")
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("POD", "STATUS", "FREED SPYRE CARDS")
+
+	for _, pod := range pods {
+		freedCards := "-"
+		if status[pod.name] != "Failed" && len(pod.spyreCards) > 0 {
+			freedCards = strings.Join(pod.spyreCards, ", ")
+		}
+		printer.AppendRow(pod.name, status[pod.name], freedCards)
+	}
+}
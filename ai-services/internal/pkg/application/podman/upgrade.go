@@ -0,0 +1,530 @@
+package podman
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	k8syaml "sigs.k8s.io/yaml"
+
+	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/journal"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	clipodman "github.com/project-ai-services/ai-services/internal/pkg/cli/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/models"
+	runtimeTypes "github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/spyre"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+// upgradeAction is what the upgrade does with a pod of the application.
+type upgradeAction string
+
+const (
+	upgradeKeep    upgradeAction = "Unchanged"
+	upgradeReplace upgradeAction = "Replace"
+	upgradeCreate  upgradeAction = "Create"
+)
+
+// podUpgrade is the upgrade of a single pod of the application.
+type podUpgrade struct {
+	template string
+	podSpec  *models.PodSpec
+	action   upgradeAction
+	timeouts templates.Timeouts
+	// current is the stored kube YAML the pod is deployed with, nil if it is unknown
+	current []byte
+	// rendered is the kube YAML the pod is upgraded to
+	rendered []byte
+	// currentCards and spyreCards map a container name to its Spyre cards, before and after the upgrade
+	currentCards map[string][]string
+	spyreCards   map[string][]string
+}
+
+// Upgrade re-renders the pod templates of a deployed application with the templates of this release and the
+// given values, and compares them with the specs the pods are deployed with. The pods which changed are replaced
+// one at a time in dependency order, each one having to pass its readiness checks before the next one is replaced.
+// A pod failing its readiness checks is rolled back to its previous spec, and the upgrade stops.
+// The values the application is deployed with are reused, the given values applying on top of them.
+// Containers keep their Spyre cards, unless the number of cards they require changed.
+func (p *PodmanApplication) Upgrade(ctx context.Context, opts types.UpgradeOptions) error {
+	templateName, pods, err := p.deployedApplication(opts.Name)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Upgrading application '%s' using template '%s'\n", opts.Name, templateName)
+	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
+
+	valuesFiles, argParams, cleanup, err := withStoredOverrides(opts.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	opts.ValuesFiles, opts.ArgParams = valuesFiles, argParams
+
+	appMetadata, tmpls, err := p.loadEnabledTemplates(tp, templateName, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return err
+	}
+
+	if !opts.DryRun {
+		defer p.releaseSpyreReservations(opts.Name)
+	}
+
+	upgrades, removed, err := p.planUpgrade(tp, opts, templateName, appMetadata, tmpls, pods)
+	if err != nil {
+		return err
+	}
+
+	for _, podName := range removed {
+		logger.Warningf("Pod '%s' is not part of the application template anymore, keeping it as it is\n", podName)
+	}
+
+	if err := compareStoredSpecs(opts.Name, upgrades); err != nil {
+		return err
+	}
+
+	printUpgradePlan(upgrades)
+
+	pending := slices.DeleteFunc(slices.Clone(upgrades), func(u *podUpgrade) bool {
+		return u.action == upgradeKeep
+	})
+	if len(pending) == 0 {
+		logger.Infof("Application '%s' is up to date, no pod to upgrade\n", opts.Name)
+
+		return nil
+	}
+
+	if opts.DryRun {
+		return printUpgradeDiff(pending)
+	}
+
+	createOpts := types.CreateOptions{
+		Name:            opts.Name,
+		TemplateName:    templateName,
+		ValuesFiles:     opts.ValuesFiles,
+		ArgParams:       opts.ArgParams,
+		ImagePullPolicy: opts.ImagePullPolicy,
+	}
+	if err := p.downloadImagesForTemplate(createOpts); err != nil {
+		return err
+	}
+
+	if !opts.SkipModelDownload {
+		if err := p.downloadModels(ctx, createOpts); err != nil {
+			return err
+		}
+	}
+
+	jrnl, err := journal.Load(opts.Name)
+	if errors.Is(err, journal.ErrNotFound) {
+		jrnl, err = journal.New(opts.Name, templateName)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, u := range pending {
+		if err := p.upgradePod(opts.Name, u, jrnl); err != nil {
+			logger.Infof("%d of %d pods upgraded before the failure\n", i, len(pending))
+
+			return fmt.Errorf("failed to upgrade application '%s': %w", opts.Name, err)
+		}
+	}
+
+	if err := saveState(tp, opts.Name, appMetadata, opts.ValuesFiles, opts.ArgParams); err != nil {
+		return err
+	}
+
+	logger.Infof("Application '%s' upgraded successfully\n", opts.Name)
+
+	return nil
+}
+
+// deployedApplication returns the template the application is deployed with, along with its pods.
+func (p *PodmanApplication) deployedApplication(appName string) (string, []runtimeTypes.Pod, error) {
+	pods, err := p.runtime.ListPods(map[string][]string{
+		"label": {fmt.Sprintf("ai-services.io/application=%s", appName)},
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	if len(pods) == 0 {
+		return "", nil, fmt.Errorf("application '%s' does not exist", appName)
+	}
+
+	templateName := pods[0].Labels[string(vars.TemplateLabel)]
+	if templateName == "" {
+		return "", nil, fmt.Errorf("failed to find the template of application '%s' from the '%s' label of its pods", appName, vars.TemplateLabel)
+	}
+
+	return templateName, pods, nil
+}
+
+// loadEnabledTemplates loads the metadata of the template along with its pod templates enabled by the values.
+func (p *PodmanApplication) loadEnabledTemplates(tp templates.Template, templateName string, valuesFiles []string,
+	argParams map[string]string) (*templates.AppMetadata, map[string]*template.Template, error) {
+	tmpls, err := tp.LoadAllTemplates(templateName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the templates: %w", err)
+	}
+
+	appMetadata, err := tp.LoadMetadata(templateName, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the app metadata: %w", err)
+	}
+
+	if err := p.verifyPodTemplateExists(tmpls, appMetadata); err != nil {
+		return nil, nil, fmt.Errorf("failed to verify pod template: %w", err)
+	}
+
+	tmpls, err = tp.LoadEnabledPodTemplates(templateName, valuesFiles, argParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to evaluate enabled pod templates: %w", err)
+	}
+
+	return appMetadata, tmpls, nil
+}
+
+// planUpgrade renders the enabled pod templates in dependency order and decides, for each pod, whether it is
+// replaced or created. The Spyre cards of the containers whose card count did not change are kept, the others are
+// reserved (or simulated, for a dry run) out of the free cards. The names of the deployed pods which are not part
+// of the template anymore are returned along with the upgrades.
+func (p *PodmanApplication) planUpgrade(tp templates.Template, opts types.UpgradeOptions, templateName string,
+	appMetadata *templates.AppMetadata, tmpls map[string]*template.Template, pods []runtimeTypes.Pod) ([]*podUpgrade, []string, error) {
+	values, err := tp.LoadValues(appMetadata.Name, opts.ValuesFiles, opts.ArgParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load params for application: %w", err)
+	}
+
+	graph, err := appMetadata.BuildPodGraph(values)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build pod dependency graph: %w", err)
+	}
+
+	deployed := make(map[string]runtimeTypes.Pod, len(pods))
+	for _, pod := range pods {
+		deployed[pod.Name] = pod
+	}
+
+	var upgrades []*podUpgrade
+	var requests []spyre.Request
+	placement := spyre.Placement{}
+
+	// the upgrades are planned in dependency order, which the pods are replaced in
+	for _, podTemplateName := range slices.Concat(graph.Layers()...) {
+		podSpec, err := p.fetchPodSpec(tp, templateName, podTemplateName, opts.Name, opts.ValuesFiles, opts.ArgParams)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		u := &podUpgrade{
+			template: podTemplateName,
+			podSpec:  podSpec,
+			action:   upgradeCreate,
+			timeouts: appMetadata.PodTimeouts(podTemplateName),
+		}
+
+		if pod, ok := deployed[podSpec.Name]; ok {
+			u.action = upgradeReplace
+			if u.currentCards, err = p.podSpyreCards(pod); err != nil {
+				return nil, nil, err
+			}
+			delete(deployed, podSpec.Name)
+		}
+
+		podRequests, err := p.spyreRequestsForPod(podSpec)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, req := range podRequests {
+			if cards := u.currentCards[req.Container]; keepsSpyreCards(req, cards) {
+				if placement[req.Pod] == nil {
+					placement[req.Pod] = map[string][]string{}
+				}
+				placement[req.Pod][req.Container] = cards
+
+				continue
+			}
+			requests = append(requests, req)
+		}
+
+		upgrades = append(upgrades, u)
+	}
+
+	if err := p.placeUpgradeSpyreCards(opts, requests, placement); err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.renderUpgrades(opts.Name, appMetadata, values, tmpls, placement, upgrades); err != nil {
+		return nil, nil, err
+	}
+
+	return upgrades, slices.Sorted(maps.Keys(deployed)), nil
+}
+
+// keepsSpyreCards reports whether the container can keep the cards it is deployed with.
+func keepsSpyreCards(req spyre.Request, cards []string) bool {
+	if len(cards) != req.Count {
+		return false
+	}
+
+	if len(req.Pinned) == 0 {
+		return true
+	}
+
+	return slices.Equal(slices.Sorted(slices.Values(req.Pinned)), slices.Sorted(slices.Values(cards)))
+}
+
+// placeUpgradeSpyreCards adds the cards of the requests to the placement, reserving them in the ledger.
+func (p *PodmanApplication) placeUpgradeSpyreCards(opts types.UpgradeOptions, requests []spyre.Request, placement spyre.Placement) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	var placed spyre.Placement
+	var err error
+	if opts.DryRun {
+		placed, err = spyre.Simulate(requests)
+	} else {
+		placed, err = spyre.Reserve(p.runtime, opts.Name, requests)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to allocate Spyre Cards: %w", err)
+	}
+
+	for pod, containers := range placed {
+		if placement[pod] == nil {
+			placement[pod] = map[string][]string{}
+		}
+		for container, cards := range containers {
+			placement[pod][container] = cards
+		}
+	}
+
+	return nil
+}
+
+// renderUpgrades renders the pod templates with the placement.
+func (p *PodmanApplication) renderUpgrades(appName string, appMetadata *templates.AppMetadata, values map[string]any,
+	tmpls map[string]*template.Template, placement spyre.Placement, upgrades []*podUpgrade) error {
+	globalParams, err := globalTemplateParams(appName, appMetadata, values)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range upgrades {
+		env, err := p.returnEnvParamsForPod(u.podSpec, p.fetchPodAnnotations(u.podSpec), placement)
+		if err != nil {
+			return fmt.Errorf("'%s': Failed to fetch env params: %w", u.template, err)
+		}
+
+		params := utils.CopyMap(globalParams)
+		params["env"] = env
+		u.spyreCards = spyreCardsFromEnv(env)
+
+		u.rendered, err = renderPodTemplate(tmpls[u.template], params)
+		if err != nil {
+			return fmt.Errorf("'%s': Failed to parse pod template: %w", u.template, err)
+		}
+	}
+
+	return nil
+}
+
+// compareStoredSpecs compares the rendered specs with the stored specs the pods are deployed with,
+// and keeps the pods whose spec did not change.
+func compareStoredSpecs(appName string, upgrades []*podUpgrade) error {
+	for _, u := range upgrades {
+		if u.action == upgradeCreate {
+			continue
+		}
+
+		var err error
+		u.current, err = state.LoadPodSpec(appName, u.template)
+		if errors.Is(err, state.ErrNotFound) {
+			logger.Warningf("'%s': No stored spec for pod '%s', it is replaced and cannot be rolled back\n",
+				u.template, u.podSpec.Name)
+
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(u.current, u.rendered) {
+			u.action = upgradeKeep
+		}
+	}
+
+	return nil
+}
+
+// podSpyreCards returns the Spyre cards set in the env of each container of the pod, in the order they are set.
+func (p *PodmanApplication) podSpyreCards(pod runtimeTypes.Pod) (map[string][]string, error) {
+	cards := map[string][]string{}
+
+	for _, container := range pod.Containers {
+		cInfo, err := p.runtime.InspectContainer(container.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container %s: %w", container.Name, err)
+		}
+
+		if addresses := strings.Fields(cInfo.Env[string(constants.PCIAddressKey)]); len(addresses) > 0 {
+			cards[strings.TrimPrefix(cInfo.Name, pod.Name+"-")] = addresses
+		}
+	}
+
+	return cards, nil
+}
+
+// upgradePod replaces the pod with its upgraded spec, or creates it if it is new. If the upgraded pod fails
+// to deploy or to become ready, it is removed and the pod is deployed again with its previous spec.
+func (p *PodmanApplication) upgradePod(appName string, u *podUpgrade, jrnl *journal.Journal) error {
+	if u.action == upgradeReplace {
+		logger.Infof("'%s': Replacing pod '%s'...\n", u.template, u.podSpec.Name)
+		if err := p.runtime.DeletePod(u.podSpec.Name, utils.BoolPtr(true)); err != nil {
+			return fmt.Errorf("failed to remove pod '%s': %w", u.podSpec.Name, err)
+		}
+	} else {
+		logger.Infof("'%s': Creating pod '%s'...\n", u.template, u.podSpec.Name)
+	}
+
+	err := p.playPod(appName, u.template, u.podSpec, u.rendered, u.spyreCards, u.timeouts, jrnl)
+	if err == nil {
+		return nil
+	}
+
+	logger.Warningf("'%s': Upgraded pod '%s' failed, rolling it back: %v\n", u.template, u.podSpec.Name, err)
+	if rollbackErr := p.rollbackPodUpgrade(appName, u, jrnl); rollbackErr != nil {
+		return errors.Join(err, rollbackErr)
+	}
+
+	return err
+}
+
+// playPod deploys the rendered pod spec, records the pod along with its Spyre cards and waits for it to be ready.
+func (p *PodmanApplication) playPod(appName, podTemplateName string, podSpec *models.PodSpec, rendered []byte,
+	spyreCards map[string][]string, timeouts templates.Timeouts, jrnl *journal.Journal) error {
+	timeouts, err := clipodman.ResolvePodTimeouts(podSpec, timeouts)
+	if err != nil {
+		return fmt.Errorf("'%s': %w", podTemplateName, err)
+	}
+
+	deployOpts := clipodman.ConstructPodDeployOptions(p.fetchPodAnnotations(podSpec))
+	pods, err := clipodman.DeployPod(p.runtime, podTemplateName, bytes.NewReader(rendered), deployOpts)
+	if err != nil {
+		return fmt.Errorf("'%s': Failed to deploy pod: %w", podTemplateName, err)
+	}
+
+	if err := jrnl.MarkPodPlayed(podTemplateName, podSpec.Name, spyreCards); err != nil {
+		return err
+	}
+
+	if err := spyre.Reassign(appName, podSpec.Name, spyreCards); err != nil {
+		return fmt.Errorf("'%s': Failed to record the allocated Spyre cards: %w", podTemplateName, err)
+	}
+
+	if err := state.SavePodSpec(appName, podTemplateName, rendered); err != nil {
+		return fmt.Errorf("'%s': %w", podTemplateName, err)
+	}
+
+	if err := clipodman.PodReadinessCheck(p.runtime, podSpec, podTemplateName, pods, timeouts); err != nil {
+		return fmt.Errorf("'%s': Failed readiness check: %w", podTemplateName, err)
+	}
+
+	return jrnl.MarkPodReady(podTemplateName)
+}
+
+// rollbackPodUpgrade removes the upgraded pod, and deploys the pod again with the spec it was deployed with.
+func (p *PodmanApplication) rollbackPodUpgrade(appName string, u *podUpgrade, jrnl *journal.Journal) error {
+	podName := u.podSpec.Name
+
+	exists, err := p.runtime.PodExists(podName)
+	if err != nil {
+		return fmt.Errorf("failed to check pod status: %w", err)
+	}
+
+	if exists {
+		if err := p.runtime.DeletePod(podName, utils.BoolPtr(true)); err != nil {
+			return fmt.Errorf("failed to remove the upgraded pod '%s': %w", podName, err)
+		}
+	}
+
+	if u.action == upgradeCreate || u.current == nil {
+		if err := spyre.ReleasePods(appName, []string{podName}); err != nil {
+			return fmt.Errorf("failed to release the Spyre cards of pod '%s': %w", podName, err)
+		}
+
+		if err := jrnl.ForgetPod(podName); err != nil {
+			return err
+		}
+
+		if u.action == upgradeReplace {
+			return fmt.Errorf("cannot restore pod '%s' as the spec it was deployed with is not stored, "+
+				"use 'ai-services application create' to deploy it again", podName)
+		}
+
+		return nil
+	}
+
+	var podSpec models.PodSpec
+	if err := k8syaml.Unmarshal(u.current, &podSpec); err != nil {
+		return fmt.Errorf("unable to read the stored spec of pod '%s': %w", podName, err)
+	}
+
+	if err := p.playPod(appName, u.template, &podSpec, u.current, u.currentCards, u.timeouts, jrnl); err != nil {
+		return fmt.Errorf("failed to restore pod '%s': %w", podName, err)
+	}
+
+	logger.Infof("'%s': Restored pod '%s' with its previous spec\n", u.template, podName)
+
+	return nil
+}
+
+func printUpgradePlan(upgrades []*podUpgrade) {
+	logger.Infoln("Upgrade plan:")
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("POD", "TEMPLATE", "ACTION")
+	for _, u := range upgrades {
+		printer.AppendRow(u.podSpec.Name, u.template, string(u.action))
+	}
+}
+
+// printUpgradeDiff prints to stdout the diff between the deployed and the upgraded spec of each pod.
+func printUpgradeDiff(upgrades []*podUpgrade) error {
+	for _, u := range upgrades {
+		diff, err := utils.UnifiedDiff(u.podSpec.Name+" (deployed)", u.podSpec.Name+" (upgraded)", string(u.current), string(u.rendered))
+		if err != nil {
+			return err
+		}
+
+		header := fmt.Sprintf("# %s: %s\n", u.template, u.action)
+		if u.action == upgradeReplace && u.current == nil {
+			header += "# the deployed spec is not stored, showing the upgraded spec only\n"
+		}
+
+		if _, err := fmt.Fprint(os.Stdout, header+diff); err != nil {
+			return fmt.Errorf("failed to print the upgrade diff: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package podman
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/application/journal"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/state"
+	"github.com/project-ai-services/ai-services/internal/pkg/application/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+// GetValues returns the values the application is deployed with, as stored by create and upgrade.
+func (p *PodmanApplication) GetValues(opts types.GetValuesOptions) (map[string]any, error) {
+	var values map[string]any
+	var err error
+
+	if opts.All {
+		values, err = state.LoadValues(opts.Name)
+	} else {
+		var m *state.Metadata
+		if m, err = state.Load(opts.Name); err == nil {
+			values = m.Overrides.Combined()
+		}
+	}
+
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, fmt.Errorf("no values are stored for application '%s', they are stored when it is created or upgraded: %w", opts.Name, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.ShowSecrets {
+		return state.RedactValues(values), nil
+	}
+
+	return state.RevealValues(values)
+}
+
+// withStoredOverrides applies the given values files and params on top of the ones the application was
+// deployed with, so that a command on an existing application reuses them. The merged values files are
+// written to a temporary values file, to be removed with the returned cleanup function.
+func withStoredOverrides(appName string, valuesFiles []string, argParams map[string]string) ([]string, map[string]string, func(), error) {
+	m, err := state.Load(appName)
+	if errors.Is(err, state.ErrNotFound) {
+		return valuesFiles, argParams, func() {}, nil
+	}
+	if err != nil {
+		return nil, nil, func() {}, err
+	}
+	logger.Infof("Reusing the values application '%s' was deployed with\n", appName)
+
+	return withOverrides(appName, m.Overrides, valuesFiles, argParams)
+}
+
+// withResumedOverrides applies the given values files and params on top of the ones the interrupted create of the
+// application was run with, as recorded in its journal, see withStoredOverrides.
+func withResumedOverrides(appName string, valuesFiles []string, argParams map[string]string) ([]string, map[string]string, func(), error) {
+	jrnl, err := journal.Load(appName)
+	if err != nil {
+		return nil, nil, func() {}, fmt.Errorf("cannot resume create of application '%s': %w", appName, err)
+	}
+	if jrnl.Overrides == nil {
+		return nil, nil, func() {}, fmt.Errorf("cannot resume create of application '%s': the values it was run with are not recorded, "+
+			"delete the application and create it again", appName)
+	}
+	logger.Infof("Reusing the values the create of application '%s' was run with\n", appName)
+
+	return withOverrides(appName, *jrnl.Overrides, valuesFiles, argParams)
+}
+
+// withOverrides applies the given values files and params on top of the stored overrides, their secrets encrypted.
+func withOverrides(appName string, storedOverrides state.Overrides, valuesFiles []string, argParams map[string]string) ([]string, map[string]string, func(), error) {
+	cleanup := func() {}
+
+	stored, err := storedOverrides.Reveal()
+	if err != nil {
+		return nil, nil, cleanup, fmt.Errorf("failed to read the stored values of application '%s': %w", appName, err)
+	}
+
+	overrides, err := state.NewOverrides(valuesFiles, argParams)
+	if err != nil {
+		return nil, nil, cleanup, err
+	}
+
+	merged := stored.With(overrides)
+
+	if len(merged.Values) == 0 {
+		return nil, merged.Params, cleanup, nil
+	}
+
+	valuesFile, err := merged.WriteValuesFile(appName)
+	if err != nil {
+		return nil, nil, cleanup, err
+	}
+
+	return []string{valuesFile}, merged.Params, func() { _ = os.Remove(valuesFile) }, nil
+}
+
+// recordOverrides records the values files and params of the create in its journal, the secrets encrypted.
+func recordOverrides(jrnl *journal.Journal, valuesFiles []string, argParams map[string]string) error {
+	overrides, err := state.NewOverrides(valuesFiles, argParams)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := overrides.Encrypt()
+	if err != nil {
+		return err
+	}
+
+	return jrnl.SetOverrides(encrypted)
+}
+
+// saveState stores the metadata and the values the application is deployed with.
+func saveState(tp templates.Template, appName string, appMetadata *templates.AppMetadata, valuesFiles []string, argParams map[string]string) error {
+	values, err := tp.LoadValues(appMetadata.Name, valuesFiles, argParams)
+	if err != nil {
+		return fmt.Errorf("failed to load params for application: %w", err)
+	}
+
+	overrides, err := state.NewOverrides(valuesFiles, argParams)
+	if err != nil {
+		return err
+	}
+
+	err = state.Save(state.Metadata{
+		Application:     appName,
+		Template:        appMetadata.Name,
+		TemplateVersion: appMetadata.Version,
+		CLIVersion:      vars.CLIVersion,
+		Overrides:       overrides,
+	}, values)
+	if err != nil {
+		return fmt.Errorf("failed to store the state of application '%s': %w", appName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package preflight
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+const (
+	meminfoPath = "/proc/meminfo"
+	bytesPerKB  = 1024
+	// modelSizeTimeout bounds the lookup of the sizes of all the models
+	modelSizeTimeout = 10 * time.Second
+)
+
+// availableMemory returns the memory available for new workloads, as reported by MemAvailable in /proc/meminfo.
+func availableMemory() (uint64, error) {
+	f, err := os.Open(meminfoPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read host memory: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Eg:- MemAvailable:   263452852 kB
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse available memory '%s': %w", fields[1], err)
+		}
+
+		return kb * bytesPerKB, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read host memory: %w", err)
+	}
+
+	return 0, fmt.Errorf("MemAvailable is not reported in %s", meminfoPath)
+}
+
+func onlineCPUs() int {
+	return runtime.NumCPU()
+}
+
+// availableDiskSpace returns the free space of the filesystem holding the directory,
+// looking at the closest existing parent if the directory is not created yet.
+func availableDiskSpace(dir string) (uint64, error) {
+	for {
+		var stat syscall.Statfs_t
+		err := syscall.Statfs(dir, &stat)
+		if err == nil {
+			return uint64(stat.Bavail) * uint64(stat.Bsize), nil //nolint:gosec,unconvert // field types differ across platforms
+		}
+
+		parent := filepath.Dir(dir)
+		if !os.IsNotExist(err) || parent == dir {
+			return 0, fmt.Errorf("failed to check free disk space of %s: %w", dir, err)
+		}
+		dir = parent
+	}
+}
+
+// modelSizes looks the sizes of the models up in parallel, all of them within modelSizeTimeout, so that an unreachable
+// source delays the create once rather than once per model. The models whose size could not be looked up are left out.
+func modelSizes(models []string, modelSize func(ctx context.Context, model string) (int64, error)) map[string]uint64 {
+	sizes := make(map[string]uint64, len(models))
+	if len(models) == 0 || modelSize == nil {
+		return sizes
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), modelSizeTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	_ = utils.ForEachParallel(models, len(models), func(model string) error {
+		size, err := modelSize(ctx, model)
+		if err != nil {
+			logger.Infof("Failed to fetch the size of model %s: %v\n", model, err, logger.VerbosityLevelDebug)
+
+			return nil
+		}
+
+		mu.Lock()
+		sizes[model] = uint64(max(size, 0))
+		mu.Unlock()
+
+		return nil
+	})
+
+	return sizes
+}
@@ -0,0 +1,276 @@
+// Package preflight checks that the host has enough capacity to deploy an application, before
+// anything is pulled, downloaded or deployed: memory, CPU, disk space for the models and host ports.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	v1 "github.com/containers/podman/v5/pkg/k8s.io/api/core/v1"
+	"github.com/containers/podman/v5/pkg/k8s.io/apimachinery/pkg/api/resource"
+
+	clipodman "github.com/project-ai-services/ai-services/internal/pkg/cli/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/models"
+	"github.com/project-ai-services/ai-services/internal/pkg/specs"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// Status is the outcome of a pre-flight check.
+type Status string
+
+const (
+	StatusOK   Status = "OK"
+	StatusFail Status = "FAIL"
+	// StatusUnknown is reported when the host capacity cannot be determined, it never fails the pre-flight
+	StatusUnknown Status = "UNKNOWN"
+)
+
+// Result is the result of a single pre-flight check.
+type Result struct {
+	Check     string
+	Required  string
+	Available string
+	Status    Status
+	Details   string
+}
+
+// Input holds what the application to be deployed requires from the host.
+type Input struct {
+	// Pods are the rendered specs of the pods to be deployed, existing pods excluded
+	Pods []*models.PodSpec
+	// Models are the models to be downloaded
+	Models []string
+	// ModelSize returns the size of a model in the source it is downloaded from, the disk check is unknown without it
+	ModelSize func(ctx context.Context, model string) (int64, error)
+	// ModelDirectory is the host directory the models are downloaded to
+	ModelDirectory string
+}
+
+// Run runs all the pre-flight checks.
+func Run(input Input) []Result {
+	logger.Infoln("Running pre-flight checks on host capacity...", logger.VerbosityLevelDebug)
+
+	pods := runningPods(input.Pods)
+
+	results := []Result{
+		checkMemory(pods),
+		checkCPU(pods),
+		checkDisk(input.Models, input.ModelSize, input.ModelDirectory),
+	}
+
+	return append(results, checkPorts(input.Pods)...)
+}
+
+// Failed returns the results of the checks which failed.
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, result := range results {
+		if result.Status == StatusFail {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+// Print prints the results as a table.
+func Print(results []Result) {
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("CHECK", "REQUIRED", "AVAILABLE", "STATUS", "DETAILS")
+
+	for _, result := range results {
+		details := result.Details
+		if details == "" {
+			details = "-"
+		}
+		printer.AppendRow(result.Check, result.Required, result.Available, string(result.Status), details)
+	}
+}
+
+// runningPods drops the pods which are not started on deploy, as they do not consume memory or CPU until started.
+func runningPods(pods []*models.PodSpec) []*models.PodSpec {
+	var running []*models.PodSpec
+	for _, pod := range pods {
+		if specs.FetchPodAnnotations(*pod)[constants.PodStartAnnotationkey] == constants.PodStartOff {
+			continue
+		}
+		running = append(running, pod)
+	}
+
+	return running
+}
+
+// containerRequest returns the request of the container for the resource, falling back to its limit.
+func containerRequest(container v1.Container, name v1.ResourceName) (resource.Quantity, bool) {
+	if q, ok := container.Resources.Requests[name]; ok {
+		return q, true
+	}
+	q, ok := container.Resources.Limits[name]
+
+	return q, ok
+}
+
+func checkMemory(pods []*models.PodSpec) Result {
+	var required int64
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if q, ok := containerRequest(container, v1.ResourceMemory); ok {
+				required += q.Value()
+			}
+		}
+	}
+
+	result := Result{Check: "Memory", Required: formatBytes(uint64(max(required, 0)))}
+
+	available, err := availableMemory()
+	if err != nil {
+		result.Available, result.Status, result.Details = "-", StatusUnknown, err.Error()
+
+		return result
+	}
+	result.Available = formatBytes(available)
+
+	if uint64(max(required, 0)) > available {
+		result.Status, result.Details = StatusFail, "not enough free memory on the host"
+
+		return result
+	}
+	result.Status = StatusOK
+
+	return result
+}
+
+func checkCPU(pods []*models.PodSpec) Result {
+	var requiredMilli int64
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if q, ok := containerRequest(container, v1.ResourceCPU); ok {
+				requiredMilli += q.MilliValue()
+			}
+		}
+	}
+
+	const milliPerCPU = 1000
+	available := onlineCPUs()
+
+	result := Result{
+		Check:     "CPU",
+		Required:  strconv.FormatFloat(float64(requiredMilli)/milliPerCPU, 'f', -1, 64),
+		Available: strconv.Itoa(available),
+		Status:    StatusOK,
+	}
+
+	if requiredMilli > int64(available)*milliPerCPU {
+		result.Status, result.Details = StatusFail, "not enough CPUs on the host"
+	}
+
+	return result
+}
+
+func checkDisk(modelList []string, modelSize func(ctx context.Context, model string) (int64, error), modelDirectory string) Result {
+	result := Result{Check: "Disk (" + modelDirectory + ")"}
+
+	// models already downloaded take no additional space
+	var missing []string
+	for _, model := range modelList {
+		if !utils.FileExists(filepath.Join(modelDirectory, model)) {
+			missing = append(missing, model)
+		}
+	}
+
+	var required uint64
+	var unknownSizes []string
+	sizes := modelSizes(missing, modelSize)
+	for _, model := range missing {
+		size, ok := sizes[model]
+		if !ok {
+			unknownSizes = append(unknownSizes, model)
+
+			continue
+		}
+		required += size
+	}
+	result.Required = formatBytes(required)
+
+	available, err := availableDiskSpace(modelDirectory)
+	if err != nil {
+		result.Available, result.Status, result.Details = "-", StatusUnknown, err.Error()
+
+		return result
+	}
+	result.Available = formatBytes(available)
+
+	switch {
+	case required > available:
+		result.Status, result.Details = StatusFail, "not enough free space to download the models"
+	case len(unknownSizes) > 0:
+		result.Status, result.Details = StatusUnknown, fmt.Sprintf("size unknown for models: %v", unknownSizes)
+	default:
+		result.Status = StatusOK
+	}
+
+	return result
+}
+
+// checkPorts checks that the fixed host ports published by the pods are free, and not published twice.
+func checkPorts(pods []*models.PodSpec) []Result {
+	var results []Result
+	publishedBy := map[string]string{}
+
+	for _, pod := range pods {
+		hostPorts := slices.Sorted(maps.Values(clipodman.FetchHostPortMappingFromAnnotation(specs.FetchPodAnnotations(*pod))))
+		for _, hostPort := range hostPorts {
+			// ports without a fixed host port are assigned a random free one
+			if hostPort == "" || hostPort == "0" {
+				continue
+			}
+
+			result := Result{Check: "Port " + hostPort, Required: pod.Name, Available: "free", Status: StatusOK}
+
+			if otherPod, ok := publishedBy[hostPort]; ok {
+				result.Available, result.Status = "in use", StatusFail
+				result.Details = fmt.Sprintf("also published by pod '%s'", otherPod)
+			} else if err := portFree(hostPort); err != nil {
+				result.Available, result.Status, result.Details = "in use", StatusFail, err.Error()
+			}
+			publishedBy[hostPort] = pod.Name
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func portFree(port string) error {
+	l, err := net.Listen("tcp", net.JoinHostPort("", port))
+	if err != nil {
+		return fmt.Errorf("port is already in use on the host: %w", err)
+	}
+
+	return l.Close()
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,265 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+const (
+	metadataFileName = "metadata.json"
+	valuesFileName   = "values.yaml"
+)
+
+// Metadata describes what an application is deployed with, stored under
+// /var/lib/ai-services/applications/<name>/metadata.json. Secret values are stored encrypted.
+type Metadata struct {
+	Application     string `json:"application"`
+	Template        string `json:"template"`
+	TemplateVersion string `json:"templateVersion"`
+	// CLIVersion is the version of the CLI which last deployed the application
+	CLIVersion string    `json:"cliVersion"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	// Overrides are the values provided on top of the defaults of the template
+	Overrides Overrides `json:"overrides"`
+}
+
+// Overrides are the values provided by the user, in the form they are provided to create.
+type Overrides struct {
+	// Values are the values files, merged in the order they are provided
+	Values map[string]any `json:"values,omitempty"`
+	// Params are the inline params, which take precedence over the values files
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// NewOverrides reads the values files and returns them along with the params as overrides.
+// The values files are merged the way the template values are overridden: later files replace the top level keys of earlier ones.
+func NewOverrides(valuesFiles []string, params map[string]string) (Overrides, error) {
+	overrides := Overrides{Values: map[string]any{}, Params: maps.Clone(params)}
+
+	for _, valuesFile := range valuesFiles {
+		data, err := os.ReadFile(valuesFile)
+		if err != nil {
+			return Overrides{}, fmt.Errorf("failed to read values file %s: %w", valuesFile, err)
+		}
+
+		values := map[string]any{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return Overrides{}, fmt.Errorf("failed to parse values file %s: %w", valuesFile, err)
+		}
+		maps.Copy(overrides.Values, values)
+	}
+
+	return overrides, nil
+}
+
+// With returns the overrides with next applied on top of them. The params set under a
+// top level key replaced by the values of next are dropped, as the values of next replace them.
+func (o Overrides) With(next Overrides) Overrides {
+	merged := Overrides{Values: maps.Clone(o.Values), Params: map[string]string{}}
+	if merged.Values == nil {
+		merged.Values = map[string]any{}
+	}
+	maps.Copy(merged.Values, next.Values)
+
+	for key, val := range o.Params {
+		topLevelKey, _, _ := strings.Cut(key, ".")
+		if _, replaced := next.Values[topLevelKey]; !replaced {
+			merged.Params[key] = val
+		}
+	}
+	maps.Copy(merged.Params, next.Params)
+
+	return merged
+}
+
+// Combined returns the values of the overrides with the params applied, as a single values map.
+func (o Overrides) Combined() map[string]any {
+	combined := copyValues(o.Values)
+	for key, val := range o.Params {
+		utils.SetNestedValue(combined, key, val)
+	}
+
+	return combined
+}
+
+// copyValues returns a deep copy of the nested maps of the values.
+func copyValues(values map[string]any) map[string]any {
+	out := make(map[string]any, len(values))
+	for key, val := range values {
+		if nested, ok := val.(map[string]any); ok {
+			val = copyValues(nested)
+		}
+		out[key] = val
+	}
+
+	return out
+}
+
+// WriteValuesFile writes the values of the overrides to a temporary values file in the application directory,
+// so that they can be passed to the template provider like a user values file. The file must be removed once used.
+func (o Overrides) WriteValuesFile(appName string) (string, error) {
+	if err := os.MkdirAll(Dir(appName), dirPermissions); err != nil {
+		return "", fmt.Errorf("failed to create application state directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(o.Values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal stored values: %w", err)
+	}
+
+	// created with 0600 permissions, as the values hold the secrets in clear
+	f, err := os.CreateTemp(Dir(appName), "values-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to write stored values: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(data); err != nil {
+		_ = os.Remove(f.Name())
+
+		return "", fmt.Errorf("failed to write stored values: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// Save stores the metadata along with the effective values of the application, the secrets encrypted.
+// The creation time of a previously stored metadata is kept.
+func Save(m Metadata, values map[string]any) error {
+	key, err := loadKey(true)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	m.CreatedAt, m.UpdatedAt = now, now
+	if previous, err := Load(m.Application); err == nil {
+		m.CreatedAt = previous.CreatedAt
+	}
+
+	if m.Overrides, err = m.Overrides.encrypt(key); err != nil {
+		return err
+	}
+
+	encryptedValues, err := mapSecrets(values, "", encrypter(key))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt values: %w", err)
+	}
+
+	valuesData, err := yaml.Marshal(encryptedValues)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	metadataData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal application metadata: %w", err)
+	}
+
+	if err := writeFile(filepath.Join(Dir(m.Application), valuesFileName), valuesData); err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(Dir(m.Application), metadataFileName), metadataData)
+}
+
+// Load returns the stored metadata of the application, with the secrets of the overrides
+// still encrypted, see Reveal and Redact. Returns ErrNotFound if none is stored.
+func Load(appName string) (*Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(appName), metadataFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to read application metadata: %w", err)
+	}
+
+	m := &Metadata{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse application metadata: %w", err)
+	}
+
+	return m, nil
+}
+
+// LoadValues returns the stored effective values of the application, with the secrets still
+// encrypted, see Reveal and Redact. Returns ErrNotFound if none are stored.
+func LoadValues(appName string) (map[string]any, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(appName), valuesFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to read stored values: %w", err)
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse stored values: %w", err)
+	}
+
+	return values, nil
+}
+
+// Reveal returns a copy of the overrides with their secrets decrypted.
+func (o Overrides) Reveal() (Overrides, error) {
+	values, err := mapSecrets(o.Values, "", decrypter())
+	if err != nil {
+		return Overrides{}, err
+	}
+
+	params, err := mapSecretParams(o.Params, decrypter())
+	if err != nil {
+		return Overrides{}, err
+	}
+
+	return Overrides{Values: values, Params: params}, nil
+}
+
+// Encrypt returns a copy of the overrides with their secrets encrypted, to be stored.
+func (o Overrides) Encrypt() (Overrides, error) {
+	key, err := loadKey(true)
+	if err != nil {
+		return Overrides{}, err
+	}
+
+	return o.encrypt(key)
+}
+
+func (o Overrides) encrypt(key []byte) (Overrides, error) {
+	values, err := mapSecrets(o.Values, "", encrypter(key))
+	if err != nil {
+		return Overrides{}, fmt.Errorf("failed to encrypt values: %w", err)
+	}
+
+	params, err := mapSecretParams(o.Params, encrypter(key))
+	if err != nil {
+		return Overrides{}, fmt.Errorf("failed to encrypt params: %w", err)
+	}
+
+	return Overrides{Values: values, Params: params}, nil
+}
+
+// RevealValues returns a copy of the values with their secrets decrypted.
+func RevealValues(values map[string]any) (map[string]any, error) {
+	return mapSecrets(values, "", decrypter())
+}
+
+// RedactValues returns a copy of the values with their secrets replaced by Redacted.
+func RedactValues(values map[string]any) map[string]any {
+	redacted, _ := mapSecrets(values, "", redacter)
+
+	return redacted
+}
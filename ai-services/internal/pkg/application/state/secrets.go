@@ -0,0 +1,230 @@
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+)
+
+const (
+	keyFileName       = "state.key"
+	keyLen            = 32
+	encryptedPrefix   = "ENC["
+	encryptedSuffix   = "]"
+	keyFilePermission = 0o600
+	// Redacted replaces the secret values when they are not revealed
+	Redacted = "******"
+)
+
+// secretKeyRegex matches the names of the values holding secrets, Eg:- opensearch.auth.password, backend.adminPasswordHash.
+var secretKeyRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[-_]?key|credentials?)([-_]?hash)?$`)
+
+// IsSecretKey reports whether the value of the dotted key holds a secret, based on the name of its last part.
+func IsSecretKey(dottedKey string) bool {
+	parts := strings.Split(dottedKey, ".")
+
+	return secretKeyRegex.MatchString(parts[len(parts)-1])
+}
+
+func isEncrypted(s string) bool {
+	return strings.HasPrefix(s, encryptedPrefix) && strings.HasSuffix(s, encryptedSuffix)
+}
+
+// keyPath returns the path of the key encrypting the secrets of all the applications, which is kept
+// outside of the application directories so that it survives the deletion of an application.
+func keyPath() string {
+	return filepath.Join(filepath.Dir(constants.ApplicationsPath), keyFileName)
+}
+
+// loadKey reads the key encrypting the secrets, generating it on first use if create is set.
+func loadKey(create bool) ([]byte, error) {
+	key, err := os.ReadFile(keyPath())
+	if err == nil {
+		if len(key) != keyLen {
+			return nil, fmt.Errorf("invalid application state key '%s'", keyPath())
+		}
+
+		return key, nil
+	}
+
+	if !os.IsNotExist(err) || !create {
+		return nil, fmt.Errorf("failed to read application state key: %w", err)
+	}
+
+	key = make([]byte, keyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate application state key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(keyPath()), dirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create application state directory: %w", err)
+	}
+
+	// O_EXCL, so that concurrent creates never overwrite the key another one already encrypted with
+	f, err := os.OpenFile(keyPath(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, keyFilePermission)
+	if errors.Is(err, os.ErrExist) {
+		return loadKey(false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write application state key: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(key); err != nil {
+		return nil, fmt.Errorf("failed to write application state key: %w", err)
+	}
+
+	return key, nil
+}
+
+func encrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + encryptedSuffix, nil
+}
+
+func decrypt(key []byte, encrypted string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(encrypted, encryptedPrefix), encryptedSuffix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// mapSecrets returns a deep copy of the values, with fn applied to the values of the secret keys.
+func mapSecrets(values map[string]any, prefix string, fn func(value any) (any, error)) (map[string]any, error) {
+	out := make(map[string]any, len(values))
+
+	for key, val := range values {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+
+		if nested, ok := val.(map[string]any); ok {
+			mapped, err := mapSecrets(nested, fullKey, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = mapped
+
+			continue
+		}
+
+		if !IsSecretKey(fullKey) || val == nil || val == "" {
+			out[key] = val
+
+			continue
+		}
+
+		mapped, err := fn(val)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", fullKey, err)
+		}
+		out[key] = mapped
+	}
+
+	return out, nil
+}
+
+// mapSecretParams returns a copy of the params, with fn applied to the values of the secret keys.
+func mapSecretParams(params map[string]string, fn func(value any) (any, error)) (map[string]string, error) {
+	out := make(map[string]string, len(params))
+
+	for key, val := range params {
+		if !IsSecretKey(key) || val == "" {
+			out[key] = val
+
+			continue
+		}
+
+		mapped, err := fn(val)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", key, err)
+		}
+		out[key] = fmt.Sprint(mapped)
+	}
+
+	return out, nil
+}
+
+func encrypter(key []byte) func(value any) (any, error) {
+	return func(value any) (any, error) {
+		if s, ok := value.(string); ok && isEncrypted(s) {
+			return s, nil
+		}
+
+		return encrypt(key, fmt.Sprint(value))
+	}
+}
+
+// decrypter only reads the key once an encrypted value is met, so that values
+// without secrets can be revealed on hosts lacking the key.
+func decrypter() func(value any) (any, error) {
+	var key []byte
+
+	return func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok || !isEncrypted(s) {
+			return value, nil
+		}
+
+		if key == nil {
+			var err error
+			if key, err = loadKey(false); err != nil {
+				return nil, err
+			}
+		}
+
+		return decrypt(key, s)
+	}
+}
+
+func redacter(value any) (any, error) {
+	return Redacted, nil
+}
@@ -0,0 +1,105 @@
+// Package state stores what an application was deployed with under /var/lib/ai-services/applications/<name>:
+// the metadata and the values of the deployment, and the rendered spec of each pod, so that the deployment
+// can be inspected, reproduced, and upgraded by replacing only the pods which changed.
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+)
+
+const (
+	podSpecsDirName = "pods"
+	dirPermissions  = 0o755
+	filePermissions = 0o600 // the state holds the secrets of the application
+)
+
+// ErrNotFound is returned when no state is stored for the application.
+var ErrNotFound = errors.New("no stored state found")
+
+// Dir returns the directory holding the state of the application.
+func Dir(appName string) string {
+	return filepath.Join(constants.ApplicationsPath, filepath.Base(appName))
+}
+
+// Files returns the paths of the stored state of the application: the metadata, the values and the pod specs, whose
+// secrets are encrypted with the key of the host, the pod specs also holding the Spyre cards of the host.
+func Files(appName string) []string {
+	return []string{
+		filepath.Join(Dir(appName), metadataFileName),
+		filepath.Join(Dir(appName), valuesFileName),
+		filepath.Join(Dir(appName), podSpecsDirName),
+	}
+}
+
+// podSpecPath returns the path of the rendered spec of the pod template.
+func podSpecPath(appName, podTemplate string) string {
+	return filepath.Join(Dir(appName), podSpecsDirName, filepath.Base(podTemplate))
+}
+
+// SavePodSpec stores the kube YAML the pod template was rendered into and deployed with. The spec is encrypted as a
+// whole, as it holds the secrets of the values in the env of its containers.
+func SavePodSpec(appName, podTemplate string, spec []byte) error {
+	key, err := loadKey(true)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encrypt(key, string(spec))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the spec of pod template '%s': %w", podTemplate, err)
+	}
+
+	return writeFile(podSpecPath(appName, podTemplate), []byte(encrypted))
+}
+
+// LoadPodSpec returns the stored kube YAML of the pod template, ErrNotFound if there is none.
+// The specs stored in clear by previous releases are returned as is.
+func LoadPodSpec(appName, podTemplate string) ([]byte, error) {
+	data, err := os.ReadFile(podSpecPath(appName, podTemplate))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to read the stored spec of pod template '%s': %w", podTemplate, err)
+	}
+
+	if !isEncrypted(string(data)) {
+		return data, nil
+	}
+
+	key, err := loadKey(false)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := decrypt(key, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the stored spec of pod template '%s': %w", podTemplate, err)
+	}
+
+	return []byte(spec), nil
+}
+
+// writeFile writes to a temporary file and renames it, so that an interrupted write never leaves a corrupted file behind.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("failed to create application state directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePermissions); err != nil {
+		return fmt.Errorf("failed to write application state: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write application state: %w", err)
+	}
+
+	return nil
+}
@@ -13,6 +13,9 @@ type CreateOptions struct {
 	TemplateName string
 	SkipChecks   []string
 	ArgParams    map[string]string
+	// DryRun renders the application without deploying it, the rendered specs are printed in Output format
+	DryRun bool
+	Output string
 
 	// Podman
 	SkipModelDownload bool
@@ -21,11 +24,30 @@ type CreateOptions struct {
 	Values            map[string]any
 	ImagePullPolicy   image.ImagePullPolicy
 	AutoYes           bool
+	MaxParallelism    int
+	KeepOnFailure     bool
+	Resume            bool
+	Force             bool
+	// VerifyImages, if set, fails the creation on any image not pinned to its digest or not signed as required
+	VerifyImages *image.VerifyOptions
 
 	// Openshift
 	Timeout time.Duration
 }
 
+// UpgradeOptions contains parameters for upgrading an application.
+type UpgradeOptions struct {
+	Name        string
+	ArgParams   map[string]string
+	ValuesFiles []string
+	// DryRun prints the changes to the pods without replacing them
+	DryRun bool
+
+	// Podman
+	SkipModelDownload bool
+	ImagePullPolicy   image.ImagePullPolicy
+}
+
 // DeleteOptions contains parameters for deleting an application.
 type DeleteOptions struct {
 	Name        string
@@ -61,6 +83,50 @@ type ListOptions struct {
 // InfoOptions contains parameters for displaying application info.
 type InfoOptions struct {
 	Name string
+	// ShowValues displays the values the application is deployed with, the secrets redacted
+	ShowValues bool
+}
+
+// GetValuesOptions contains parameters for fetching the values of an application.
+type GetValuesOptions struct {
+	Name string
+	// All returns the values merged with the template defaults, instead of the user supplied ones
+	All         bool
+	ShowSecrets bool
+}
+
+// DiffOptions contains parameters for detecting the drift of an application.
+type DiffOptions struct {
+	Name string
+}
+
+// Drift is a field of a deployed application differing from what the application is expected to run.
+type Drift struct {
+	// Resource is the pod, or the object on OpenShift, which drifted
+	Resource string
+	// Container is the container which drifted, empty if the drift is on the resource itself
+	Container string
+	Field     string
+	Expected  string
+	Actual    string
+}
+
+// BackupOptions contains parameters for backing up an application.
+type BackupOptions struct {
+	Name string
+	// Output is the path of the archive to write
+	Output string
+	// Live copies the data while the application runs, instead of stopping its pods during the copy
+	Live    bool
+	AutoYes bool
+}
+
+// RestoreOptions contains parameters for restoring an application from a backup.
+type RestoreOptions struct {
+	// Input is the path of the archive to restore from
+	Input             string
+	SkipModelDownload bool
+	ImagePullPolicy   image.ImagePullPolicy
 }
 
 // LogsOptions contains parameters for displaying application logs.
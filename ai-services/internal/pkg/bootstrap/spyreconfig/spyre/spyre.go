@@ -21,6 +21,8 @@ const (
 	sentientGroup     = "sentient"
 	vfioConfigFile    = "/etc/modprobe.d/vfio-pci.conf"
 	memLimitPerCard   = 134234112 // 128MB in bytes - memory lock limit per Spyre card
+	pciDevicesPath    = "/sys/bus/pci/devices"
+	noNUMANode        = -1
 )
 
 // Package-level regex patterns compiled once for performance.
@@ -60,6 +62,40 @@ func GetSpyreDevices() ([]*ghw.PCIDevice, error) {
 	return spyreDevices, nil
 }
 
+// CardTopology describes where a Spyre card sits on the host.
+type CardTopology struct {
+	PCIAddress string
+	// NUMANode is the NUMA node the card is affined to, -1 if the architecture is not NUMA
+	NUMANode int
+	// IOMMUGroup is the IOMMU group of the card, empty if it cannot be determined
+	IOMMUGroup string
+}
+
+// GetSpyreTopology returns the NUMA node and IOMMU group of all the Spyre cards, keyed by PCI address.
+func GetSpyreTopology() (map[string]CardTopology, error) {
+	devices, err := GetSpyreDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make(map[string]CardTopology, len(devices))
+	for _, device := range devices {
+		card := CardTopology{PCIAddress: device.Address, NUMANode: noNUMANode}
+		if device.Node != nil {
+			card.NUMANode = device.Node.ID
+		}
+
+		// the iommu_group of the device is a symlink to /sys/kernel/iommu_groups/<group>
+		if group, err := filepath.EvalSymlinks(filepath.Join(pciDevicesPath, device.Address, "iommu_group")); err == nil {
+			card.IOMMUGroup = filepath.Base(group)
+		}
+
+		cards[device.Address] = card
+	}
+
+	return cards, nil
+}
+
 // IsApplicable checks if Spyre validation is applicable to the current system.
 func IsApplicable() bool {
 	return GetNumberOfSpyreCards() > 0
@@ -0,0 +1,364 @@
+// Package bundle exports the container images and the models of an application template into a single tar archive,
+// to be loaded on hosts without access to the registries nor to Hugging Face. Each image is stored as an OCI archive,
+// and each model as its files, along with a manifest holding the SHA256 checksum of every file of the bundle.
+package bundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
+)
+
+const (
+	// FormatVersion is the version of the layout of the bundle, bumped on incompatible changes
+	FormatVersion = 1
+
+	// the manifest is the last entry of the bundle, as it holds the checksums computed while the bundle is written
+	manifestName    = "manifest.json"
+	imagesDir       = "images"
+	modelsDir       = "models"
+	filePermissions = 0o644
+	dirPermissions  = 0o755
+)
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	Template      string    `json:"template"`
+	CLIVersion    string    `json:"cliVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Images        []Image   `json:"images"`
+	Models        []string  `json:"models"`
+	// Checksums maps the path of each file of the bundle to its SHA256 digest
+	Checksums map[string]string `json:"checksums"`
+}
+
+// Image is a container image stored in the bundle.
+type Image struct {
+	Reference string `json:"reference"`
+	// File is the path of the OCI archive of the image in the bundle
+	File string `json:"file"`
+}
+
+// Writer writes a bundle.
+type Writer struct {
+	file     *os.File
+	path     string
+	tw       *tar.Writer
+	manifest Manifest
+}
+
+// NewWriter starts writing the bundle of the template to the path, the bundle only
+// being at the path once Close succeeds.
+func NewWriter(bundlePath string, manifest Manifest) (*Writer, error) {
+	f, err := os.OpenFile(bundlePath+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	manifest.FormatVersion = FormatVersion
+	manifest.Checksums = map[string]string{}
+
+	return &Writer{file: f, path: bundlePath, tw: tar.NewWriter(f), manifest: manifest}, nil
+}
+
+// AddImage adds the OCI archive of the image, saved from the local storage of podman.
+func (w *Writer) AddImage(client *podman.PodmanClient, ref string, index int) error {
+	// the archive is saved to a temporary file first, as its size must be known to be added to the bundle
+	tmp, err := os.CreateTemp(filepath.Dir(w.path), ".bundle-image-*.tar")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary image archive: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := client.SaveImage(ref, tmp); err != nil {
+		return err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read image archive: %w", err)
+	}
+
+	name := path.Join(imagesDir, fmt.Sprintf("%d.oci.tar", index))
+	if err := w.addFile(name, tmp); err != nil {
+		return err
+	}
+
+	w.manifest.Images = append(w.manifest.Images, Image{Reference: ref, File: name})
+
+	return nil
+}
+
+// AddModel adds the files of the model, stored under the model directory.
+func (w *Writer) AddModel(modelDir, model string) error {
+	root := filepath.Join(modelDir, model)
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(modelDir, p)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", p, err)
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		defer func() { _ = f.Close() }()
+
+		return w.addFile(path.Join(modelsDir, filepath.ToSlash(rel)), f)
+	})
+	if err != nil {
+		return err
+	}
+
+	w.manifest.Models = append(w.manifest.Models, model)
+
+	return nil
+}
+
+func (w *Writer) addFile(name string, f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name(), err)
+	}
+
+	hdr := &tar.Header{Name: name, Mode: filePermissions, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err := w.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to add %s to the bundle: %w", name, err)
+	}
+
+	h := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(w.tw, h), f, info.Size()); err != nil {
+		return fmt.Errorf("failed to add %s to the bundle: %w", name, err)
+	}
+	w.manifest.Checksums[name] = hex.EncodeToString(h.Sum(nil))
+
+	return nil
+}
+
+// Close writes the manifest and moves the bundle to its path.
+func (w *Writer) Close() error {
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to marshal bundle manifest: %w", err), w.Abort())
+	}
+
+	hdr := &tar.Header{Name: manifestName, Mode: filePermissions, Size: int64(len(data)), ModTime: time.Now(), Typeflag: tar.TypeReg}
+	if err := w.tw.WriteHeader(hdr); err != nil {
+		return errors.Join(fmt.Errorf("failed to write bundle manifest: %w", err), w.Abort())
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return errors.Join(fmt.Errorf("failed to write bundle manifest: %w", err), w.Abort())
+	}
+
+	if err := w.tw.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to write bundle: %w", err), w.Abort())
+	}
+	if err := w.file.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to write bundle: %w", err), w.Abort())
+	}
+
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return nil
+}
+
+// Abort removes the partially written bundle.
+func (w *Writer) Abort() error {
+	_ = w.file.Close()
+
+	if err := os.Remove(w.file.Name()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove partial bundle: %w", err)
+	}
+
+	return nil
+}
+
+// Load extracts the bundle, verifies the checksums of its files, and loads its images into the local storage of
+// podman and its models into the model directory. Nothing is loaded unless every file matches its checksum.
+func Load(client *podman.PodmanClient, bundlePath, modelDir string) (*Manifest, error) {
+	if err := os.MkdirAll(modelDir, dirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create model directory: %w", err)
+	}
+
+	// staged next to the models, so that they are moved in place without being copied again
+	staging, err := os.MkdirTemp(modelDir, ".bundle-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	logger.Infof("Extracting bundle '%s'...\n", bundlePath)
+	manifest, err := extract(bundlePath, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range manifest.Images {
+		if err := loadImage(client, filepath.Join(staging, filepath.FromSlash(img.File)), img.Reference); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, model := range manifest.Models {
+		if !filepath.IsLocal(model) {
+			return nil, fmt.Errorf("invalid model '%s' in the bundle manifest", model)
+		}
+
+		logger.Infof("Loading model %s...\n", model)
+		target := filepath.Join(modelDir, model)
+		if err := os.RemoveAll(target); err != nil {
+			return nil, fmt.Errorf("failed to replace model %s: %w", model, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), dirPermissions); err != nil {
+			return nil, fmt.Errorf("failed to create model directory: %w", err)
+		}
+		if err := os.Rename(filepath.Join(staging, modelsDir, model), target); err != nil {
+			return nil, fmt.Errorf("failed to load model %s: %w", model, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// extract writes the files of the bundle to the directory, and verifies them against the checksums of the manifest.
+func extract(bundlePath, dir string) (*Manifest, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var manifest *Manifest
+	checksums := map[string]string{}
+	tr := tar.NewReader(f)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle '%s': %w", bundlePath, err)
+		}
+
+		if hdr.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+			}
+
+			continue
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !filepath.IsLocal(hdr.Name) {
+			return nil, fmt.Errorf("invalid entry '%s' in bundle '%s'", hdr.Name, bundlePath)
+		}
+
+		if checksums[hdr.Name], err = extractFile(tr, filepath.Join(dir, filepath.FromSlash(hdr.Name))); err != nil {
+			return nil, err
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("'%s' is not a bundle, it has no manifest", bundlePath)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("bundle '%s' has format version %d, this release supports up to version %d",
+			bundlePath, manifest.FormatVersion, FormatVersion)
+	}
+
+	return manifest, verify(manifest, checksums)
+}
+
+func extractFile(r io.Reader, target string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(target), dirPermissions); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermissions)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", target, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verify checks that the bundle holds every file of the manifest, with the checksum it was written with.
+func verify(manifest *Manifest, checksums map[string]string) error {
+	for name, expected := range manifest.Checksums {
+		actual, ok := checksums[name]
+		if !ok {
+			return fmt.Errorf("file '%s' of the bundle manifest is missing, the bundle is incomplete", name)
+		}
+		if actual != expected {
+			return fmt.Errorf("checksum mismatch for '%s', the bundle is corrupted", name)
+		}
+	}
+
+	for name := range checksums {
+		if _, ok := manifest.Checksums[name]; !ok {
+			return fmt.Errorf("file '%s' of the bundle is not part of its manifest", name)
+		}
+	}
+
+	return nil
+}
+
+// loadImage loads the OCI archive of the image, and tags it with its reference unless the archive already does.
+func loadImage(client *podman.PodmanClient, archive, ref string) error {
+	logger.Infof("Loading image %s...\n", ref)
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to read image archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	names, err := client.LoadImage(f)
+	if err != nil {
+		return fmt.Errorf("failed to load image %s: %w", ref, err)
+	}
+
+	if slices.Contains(names, ref) {
+		return nil
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("no image loaded from the archive of image %s", ref)
+	}
+
+	return client.TagImage(names[0], ref)
+}
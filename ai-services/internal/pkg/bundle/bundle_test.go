@@ -0,0 +1,229 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func checksum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// bundleEntry is an entry of a test bundle, a header along with the content of the regular files.
+type bundleEntry struct {
+	hdr  tar.Header
+	body string
+}
+
+func regularEntry(name, body string) bundleEntry {
+	return bundleEntry{hdr: tar.Header{Name: name, Mode: filePermissions, Size: int64(len(body)), Typeflag: tar.TypeReg}, body: body}
+}
+
+// writeBundle writes the entries, followed by the manifest unless nil, to a bundle in a temporary directory.
+func writeBundle(t *testing.T, manifest *Manifest, entries ...bundleEntry) string {
+	t.Helper()
+
+	if manifest != nil {
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, regularEntry(manifestName, string(data)))
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		if err := tw.WriteHeader(&e.hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, e.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+	if err := os.WriteFile(bundlePath, buf.Bytes(), filePermissions); err != nil {
+		t.Fatal(err)
+	}
+
+	return bundlePath
+}
+
+func TestVerify(t *testing.T) {
+	manifest := &Manifest{Checksums: map[string]string{
+		"models/org/model/config.json": checksum("{}"),
+		"images/0.tar":                 checksum("image"),
+	}}
+
+	tests := []struct {
+		name      string
+		checksums map[string]string
+		wantErr   string
+	}{
+		{
+			name:      "complete",
+			checksums: map[string]string{"models/org/model/config.json": checksum("{}"), "images/0.tar": checksum("image")},
+		},
+		{
+			name:      "missing file",
+			checksums: map[string]string{"models/org/model/config.json": checksum("{}")},
+			wantErr:   "is missing",
+		},
+		{
+			name:      "checksum mismatch",
+			checksums: map[string]string{"models/org/model/config.json": checksum("{ }"), "images/0.tar": checksum("image")},
+			wantErr:   "checksum mismatch",
+		},
+		{
+			name: "extra file",
+			checksums: map[string]string{
+				"models/org/model/config.json": checksum("{}"), "images/0.tar": checksum("image"), "images/1.tar": checksum("other"),
+			},
+			wantErr: "not part of its manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, verify(manifest, tt.checksums), tt.wantErr)
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	const modelFile, modelBody = "models/org/model/config.json", "{}"
+	model := regularEntry(modelFile, modelBody)
+	manifest := func(version int) *Manifest {
+		return &Manifest{FormatVersion: version, Models: []string{"org/model"}, Checksums: map[string]string{modelFile: checksum(modelBody)}}
+	}
+
+	tests := []struct {
+		name     string
+		manifest *Manifest
+		entries  []bundleEntry
+		wantErr  string
+	}{
+		{name: "valid", manifest: manifest(FormatVersion), entries: []bundleEntry{model}},
+		{name: "no manifest", entries: []bundleEntry{model}, wantErr: "has no manifest"},
+		{name: "newer format version", manifest: manifest(FormatVersion + 1), entries: []bundleEntry{model}, wantErr: "format version"},
+		{name: "corrupted file", manifest: manifest(FormatVersion), entries: []bundleEntry{regularEntry(modelFile, "{ }")}, wantErr: "checksum mismatch"},
+		{
+			name:     "parent path",
+			manifest: manifest(FormatVersion),
+			entries:  []bundleEntry{model, regularEntry("../outside", "x")},
+			wantErr:  "invalid entry",
+		},
+		{
+			name:     "absolute path",
+			manifest: manifest(FormatVersion),
+			entries:  []bundleEntry{model, regularEntry("/tmp/outside", "x")},
+			wantErr:  "invalid entry",
+		},
+		{
+			name:     "symlink",
+			manifest: manifest(FormatVersion),
+			entries:  []bundleEntry{{hdr: tar.Header{Name: "models/org", Typeflag: tar.TypeSymlink, Linkname: "/tmp"}}, model},
+			wantErr:  "invalid entry",
+		},
+		{
+			name:     "directory",
+			manifest: manifest(FormatVersion),
+			entries:  []bundleEntry{{hdr: tar.Header{Name: "models/", Mode: dirPermissions, Typeflag: tar.TypeDir}}, model},
+			wantErr:  "invalid entry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			got, err := extract(writeBundle(t, tt.manifest, tt.entries...), dir)
+			checkError(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+
+			if len(got.Models) != 1 || got.Models[0] != "org/model" {
+				t.Errorf("extract() models = %v, want [org/model]", got.Models)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(modelFile)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != modelBody {
+				t.Errorf("extracted %s = %q, want %q", modelFile, data, modelBody)
+			}
+		})
+	}
+}
+
+// TestExtractWritten extracts a bundle written by the Writer.
+func TestExtractWritten(t *testing.T) {
+	modelDir := t.TempDir()
+	files := map[string]string{"org/model/config.json": "{}", "org/model/weights/model.safetensors": "weights"}
+	for name, body := range files {
+		p := filepath.Join(modelDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), dirPermissions); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), filePermissions); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar")
+	w, err := NewWriter(bundlePath, Manifest{Template: "rag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddModel(modelDir, "org/model"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	manifest, err := extract(bundlePath, dir)
+	if err != nil {
+		t.Fatalf("extract() failed: %v", err)
+	}
+	if manifest.Template != "rag" || len(manifest.Checksums) != len(files) {
+		t.Errorf("extract() manifest = %+v, want template rag with %d checksums", manifest, len(files))
+	}
+	for name, body := range files {
+		data, err := os.ReadFile(filepath.Join(dir, modelsDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != body {
+			t.Errorf("extracted %s = %q, want %q", name, data, body)
+		}
+	}
+}
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	switch {
+	case want == "" && err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case want != "" && err == nil:
+		t.Fatalf("expected an error containing %q", want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Fatalf("error %q does not contain %q", err, want)
+	}
+}
@@ -4,9 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
-	"errors"
 	"fmt"
-	"sync"
 	"text/template"
 
 	"github.com/project-ai-services/ai-services/assets"
@@ -134,54 +132,22 @@ func prepareCatalogValues(tp templates.Template, podmanURI, passwordHash string,
 	return tp.LoadValues(catalogAppTemplate, nil, argParams)
 }
 
-// executePodLayers executes all pod template layers.
+// executePodLayers deploys the pod templates following their dependency graph.
 func executePodLayers(rt *podman.PodmanClient, tp templates.Template, tmpls map[string]*template.Template,
 	appMetadata *templates.AppMetadata, values map[string]any, argParams map[string]string, s *spinner.Spinner) error {
-	for i, layer := range appMetadata.PodTemplateExecutions {
-		logger.Infof("\n Executing Layer %d/%d: %v\n", i+1, len(appMetadata.PodTemplateExecutions), layer)
-		logger.Infoln("-------")
-
-		if err := executeLayer(rt, tp, tmpls, layer, appMetadata.Version, values, argParams, i); err != nil {
-			s.Fail("failed to deploy catalog pod")
-
-			return err
-		}
-
-		logger.Infof("Layer %d completed\n", i+1)
-	}
-
-	return nil
-}
-
-// executeLayer executes a single layer of pod templates.
-func executeLayer(rt *podman.PodmanClient, tp templates.Template, tmpls map[string]*template.Template,
-	layer []string, version string, values map[string]any, argParams map[string]string, layerIndex int) error {
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(layer))
+	graph, err := appMetadata.BuildPodGraph(values)
+	if err != nil {
+		s.Fail("failed to build pod dependency graph")
 
-	// for each layer, fetch all the pod Template Names and do the pod deploy
-	for _, podTemplateName := range layer {
-		wg.Add(1)
-		go func(t string) {
-			defer wg.Done()
-			if err := executePodTemplate(rt, tp, tmpls, t, catalogAppTemplate, catalogAppName, values, version, nil, argParams); err != nil {
-				errCh <- err
-			}
-		}(podTemplateName)
+		return fmt.Errorf("failed to build pod dependency graph: %w", err)
 	}
 
-	wg.Wait()
-	close(errCh)
+	if err := clipodman.DeployPodGraph(graph, appMetadata.MaxParallelism, func(podTemplateName string) error {
+		return executePodTemplate(rt, tp, tmpls, podTemplateName, catalogAppTemplate, catalogAppName, values, appMetadata, nil, argParams)
+	}); err != nil {
+		s.Fail("failed to deploy catalog pod")
 
-	// collect all errors for this layer
-	errs := make([]error, 0, len(layer))
-	for e := range errCh {
-		errs = append(errs, fmt.Errorf("layer %d: %w", layerIndex+1, e))
-	}
-
-	// If an error exist for a given layer, then return (do not process further layers)
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+		return err
 	}
 
 	return nil
@@ -189,7 +155,7 @@ func executeLayer(rt *podman.PodmanClient, tp templates.Template, tmpls map[stri
 
 // executePodTemplate executes a single pod template.
 func executePodTemplate(rt *podman.PodmanClient, tp templates.Template, tmpls map[string]*template.Template,
-	podTemplateName, appTemplateName, appName string, values map[string]any, version string,
+	podTemplateName, appTemplateName, appName string, values map[string]any, appMetadata *templates.AppMetadata,
 	valuesFiles []string, argParams map[string]string) error {
 	logger.Infof("Processing template: %s\n", podTemplateName)
 
@@ -203,7 +169,7 @@ func executePodTemplate(rt *podman.PodmanClient, tp templates.Template, tmpls ma
 	params := map[string]any{
 		"AppName":         appName,
 		"AppTemplateName": appTemplateName,
-		"Version":         version,
+		"Version":         appMetadata.Version,
 		"Values":          values,
 		"env":             map[string]map[string]string{},
 	}
@@ -221,7 +187,7 @@ func executePodTemplate(rt *podman.PodmanClient, tp templates.Template, tmpls ma
 	reader := bytes.NewReader(rendered.Bytes())
 	podDeployOptions := clipodman.ConstructPodDeployOptions(specs.FetchPodAnnotations(*podSpec))
 
-	if err := clipodman.DeployPodAndReadinessCheck(rt, podSpec, podTemplateName, reader, podDeployOptions); err != nil {
+	if err := clipodman.DeployPodAndReadinessCheck(rt, podSpec, podTemplateName, reader, podDeployOptions, appMetadata.PodTimeouts(podTemplateName)); err != nil {
 		return fmt.Errorf("failed to deploy pod: %w", err)
 	}
 
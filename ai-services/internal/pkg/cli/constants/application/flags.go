@@ -7,11 +7,20 @@ type CreateFlags struct {
 	Template       string
 	Params         string
 	Values         string
+	DryRun         string
+	Output         string
 
 	// Podman-specific flags
 	SkipImageDownload string
 	SkipModelDownload string
 	ImagePullPolicy   string
+	MaxParallelism    string
+	KeepOnFailure     string
+	Resume            string
+	Force             string
+	VerifyImages      string
+	SignaturePolicy   string
+	SignatureKey      string
 
 	// OpenShift-specific flags
 	Timeout string
@@ -24,16 +33,93 @@ var Create = CreateFlags{
 	Template:       "template",
 	Params:         "params",
 	Values:         "values",
+	DryRun:         "dry-run",
+	Output:         "output",
 
 	// Podman-specific flags
 	SkipImageDownload: "skip-image-download",
 	SkipModelDownload: "skip-model-download",
 	ImagePullPolicy:   "image-pull-policy",
+	MaxParallelism:    "max-parallelism",
+	KeepOnFailure:     "keep-on-failure",
+	Resume:            "resume",
+	Force:             "force",
+	VerifyImages:      "verify-images",
+	SignaturePolicy:   "signature-policy",
+	SignatureKey:      "signature-key",
 
 	// OpenShift-specific flags
 	Timeout: "timeout",
 }
 
+// UpgradeFlags contains all flag names for the 'application upgrade' command.
+type UpgradeFlags struct {
+	// Common flags - valid for all runtimes
+	Params string
+	Values string
+	DryRun string
+
+	// Podman-specific flags
+	SkipModelDownload string
+	ImagePullPolicy   string
+}
+
+// Upgrade holds the flag constants for the 'application upgrade' command.
+var Upgrade = UpgradeFlags{
+	// Common flags
+	Params: "params",
+	Values: "values",
+	DryRun: "dry-run",
+
+	// Podman-specific flags
+	SkipModelDownload: "skip-model-download",
+	ImagePullPolicy:   "image-pull-policy",
+}
+
+// GetValuesFlags contains all flag names for the 'application get-values' command.
+type GetValuesFlags struct {
+	// Common flags - valid for all runtimes
+	All         string
+	ShowSecrets string
+}
+
+// GetValues holds the flag constants for the 'application get-values' command.
+var GetValues = GetValuesFlags{
+	// Common flags
+	All:         "all",
+	ShowSecrets: "show-secrets",
+}
+
+// BackupFlags contains all flag names for the 'application backup' command.
+type BackupFlags struct {
+	// Podman-specific flags
+	Output  string
+	Live    string
+	AutoYes string
+}
+
+// Backup holds the flag constants for the 'application backup' command.
+var Backup = BackupFlags{
+	// Podman-specific flags
+	Output:  "output",
+	Live:    "live",
+	AutoYes: "yes",
+}
+
+// RestoreFlags contains all flag names for the 'application restore' command.
+type RestoreFlags struct {
+	// Podman-specific flags
+	SkipModelDownload string
+	ImagePullPolicy   string
+}
+
+// Restore holds the flag constants for the 'application restore' command.
+var Restore = RestoreFlags{
+	// Podman-specific flags
+	SkipModelDownload: "skip-model-download",
+	ImagePullPolicy:   "image-pull-policy",
+}
+
 // DeleteFlags contains all flag names for the 'application delete' command.
 type DeleteFlags struct {
 	// Common flags - valid for all runtimes
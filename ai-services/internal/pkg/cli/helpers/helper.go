@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -12,80 +13,147 @@ import (
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 )
 
 const (
-	inspectPollInterval = 10 * time.Second
+	initialPollInterval    = 1 * time.Second
+	maxPollInterval        = 10 * time.Second
+	eventsFallbackInterval = 30 * time.Second
 )
 
-func WaitForContainerReadiness(runtime runtime.Runtime, containerNameOrId string, timeout time.Duration) error {
-	var containerStatus *types.Container
-	var err error
-
+// pollUntil calls condition until it reports done, fails or the timeout is reached.
+// The interval between the calls starts at initialPollInterval and backs off up to maxPollInterval.
+func pollUntil(timeout time.Duration, waitingFor string, condition func() (bool, error)) error {
 	deadline := time.Now().Add(timeout)
+	interval := initialPollInterval
+	backoff := utils.ExponentialBackoff(maxPollInterval)
 
 	for {
-		// fetch the container status
-		containerStatus, err = runtime.InspectContainer(containerNameOrId)
+		done, err := condition()
 		if err != nil {
-			return fmt.Errorf("failed to check container status: %w", err)
-		}
-
-		healthStatus := containerStatus.Health
-
-		if healthStatus == "" {
-			return nil
+			return err
 		}
 
-		if healthStatus == string(constants.Ready) {
+		if done {
 			return nil
 		}
 
-		// if deadline exceeds, stop the container readiness check
+		// if deadline exceeds, stop polling
 		if time.Now().After(deadline) {
-			return fmt.Errorf("operation timed out waiting for container readiness")
+			return fmt.Errorf("operation timed out waiting for %s", waitingFor)
 		}
 
-		// every 10 seconds inspect the container
-		time.Sleep(inspectPollInterval)
+		time.Sleep(min(interval, time.Until(deadline)))
+		interval = backoff(interval)
 	}
 }
 
-// WaitForContainersCreation waits until all the containers in the provided podID are created within the specified timeout.
-func WaitForContainersCreation(runtime runtime.Runtime, podID string, expectedContainerCount int, timeout time.Duration) error {
+// waitForEvents checks the condition every time a runtime event matching the filters is received, until it
+// reports done, fails or the timeout is reached. The condition is also checked every eventsFallbackInterval,
+// in case an event is missed. If the runtime cannot stream events, it falls back to polling.
+func waitForEvents(runtime runtime.Runtime, timeout time.Duration, waitingFor string, filters map[string][]string,
+	matches func(types.Event) bool, condition func() (bool, error)) error {
 	deadline := time.Now().Add(timeout)
 
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	events, err := runtime.Events(ctx, filters)
+	if err != nil {
+		logger.Infof("Unable to watch runtime events, falling back to polling: %v\n", err, logger.VerbosityLevelDebug)
+
+		return pollUntil(timeout, waitingFor, condition)
+	}
+
+	fallback := time.NewTicker(eventsFallbackInterval)
+	defer fallback.Stop()
+
+	// the condition is checked once subscribed, so that no event in between is missed
 	for {
-		// fetch the pod info
-		pInfo, err := runtime.InspectPod(podID)
+		done, err := condition()
 		if err != nil {
-			return fmt.Errorf("failed to do pod inspect for podID: %s with error: %w", podID, err)
+			return err
 		}
 
-		// if the expected count is reached, then all the containers are created
-		// Note: Adding +1 to the expectedContainerCount as there is an additional 'infra' container added to all pods by podman
-		if len(pInfo.Containers) == expectedContainerCount+1 {
+		if done {
 			return nil
 		}
 
-		// if deadline exceeds, stop the container creation check
-		if time.Now().After(deadline) {
-			return fmt.Errorf("operation timed out waiting for container creation")
+		if !nextTrigger(ctx, events, fallback.C, matches) {
+			if ctx.Err() != nil {
+				return fmt.Errorf("operation timed out waiting for %s", waitingFor)
+			}
+			logger.Infoln("Runtime events stream closed, falling back to polling", logger.VerbosityLevelDebug)
+
+			return pollUntil(time.Until(deadline), waitingFor, condition)
+		}
+	}
+}
+
+// nextTrigger blocks until a matching event is received or the fallback ticks.
+// It returns false once the events stream is closed or the context is done.
+func nextTrigger(ctx context.Context, events <-chan types.Event, fallback <-chan time.Time, matches func(types.Event) bool) bool {
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			if matches == nil || matches(event) {
+				return true
+			}
+		case <-fallback:
+			return true
+		case <-ctx.Done():
+			return false
 		}
+	}
+}
 
-		// every 10 seconds inspect the pod
-		time.Sleep(inspectPollInterval)
+func WaitForContainerReadiness(runtime runtime.Runtime, containerNameOrId string, timeout time.Duration) error {
+	filters := map[string][]string{
+		"type":      {"container"},
+		"container": {containerNameOrId},
+		"event":     {"health_status"},
 	}
+
+	return waitForEvents(runtime, timeout, "container readiness", filters, nil, func() (bool, error) {
+		// fetch the container status
+		containerStatus, err := runtime.InspectContainer(containerNameOrId)
+		if err != nil {
+			return false, fmt.Errorf("failed to check container status: %w", err)
+		}
+
+		healthStatus := containerStatus.Health
+
+		return healthStatus == "" || healthStatus == string(constants.Ready), nil
+	})
 }
 
-func FetchContainerStartPeriod(runtime runtime.Runtime, containerNameOrId string) (time.Duration, error) {
-	// fetch the container stats
-	containerStats, err := runtime.InspectContainer(containerNameOrId)
-	if err != nil {
-		return 0, fmt.Errorf("failed to check container stats: %w", err)
+// WaitForContainersCreation waits until all the containers in the provided podID are created within the specified timeout.
+func WaitForContainersCreation(runtime runtime.Runtime, podID string, expectedContainerCount int, timeout time.Duration) error {
+	filters := map[string][]string{
+		"type":  {"container"},
+		"event": {"create"},
 	}
 
-	return containerStats.HealthcheckStartPeriod, nil
+	// the events can not be filtered by pod, hence the matching is done on the received events
+	matches := func(event types.Event) bool {
+		return event.PodID == podID
+	}
+
+	return waitForEvents(runtime, timeout, "container creation", filters, matches, func() (bool, error) {
+		// fetch the pod info
+		pInfo, err := runtime.InspectPod(podID)
+		if err != nil {
+			return false, fmt.Errorf("failed to do pod inspect for podID: %s with error: %w", podID, err)
+		}
+
+		// if the expected count is reached, then all the containers are created
+		// Note: Adding +1 to the expectedContainerCount as there is an additional 'infra' container added to all pods by podman
+		return len(pInfo.Containers) == expectedContainerCount+1, nil
+	})
 }
 
 func ListSpyreCards() ([]string, error) {
@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	hfUnitBase = 1000
+	// hfMaxLine bounds the partial line kept between two writes
+	hfMaxLine = 4096
+)
+
+var (
+	// hfBarRegex matches the progress bar of a file drawn by hf download (Eg:- model.safetensors:  45%|████▌     | 2.23G/4.97G [00:30<00:37, 74.0MB/s]),
+	// the name of the file in the first group, the bytes downloaded in the second and third, the size in the fourth and fifth.
+	hfBarRegex = regexp.MustCompile(`^\s*(.+?):\s+\d+%\|[^|]*\|\s*([\d.]+)([kMGTP]?)B?/([\d.]+)([kMGTP]?)B?\b`)
+	ansiRegex  = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// HFProgressWriter parses the progress bars of the output of hf download, and reports the bytes downloaded
+// summed over the files of the model.
+type HFProgressWriter struct {
+	progress func(current, total int64)
+
+	mu      sync.Mutex
+	partial string
+	last    string
+	files   map[string][2]int64
+}
+
+// NewHFProgressWriter returns the writer parsing the output of hf download, reporting its progress.
+func NewHFProgressWriter(progress func(current, total int64)) *HFProgressWriter {
+	return &HFProgressWriter{progress: progress, files: map[string][2]int64{}}
+}
+
+// Write splits the output on the carriage returns redrawing the bars as well as on the new lines.
+func (w *HFProgressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lines := strings.FieldsFunc(w.partial+ansiRegex.ReplaceAllString(string(p), ""), func(r rune) bool {
+		return r == '\r' || r == '\n'
+	})
+	w.partial = ""
+	if len(lines) > 0 && !strings.ContainsAny(string(p[len(p)-1:]), "\r\n") {
+		w.partial = lines[len(lines)-1]
+		lines = lines[:len(lines)-1]
+		if len(w.partial) > hfMaxLine {
+			w.partial = ""
+		}
+	}
+
+	for _, line := range lines {
+		w.parse(line)
+	}
+
+	return len(p), nil
+}
+
+func (w *HFProgressWriter) parse(line string) {
+	if strings.TrimSpace(line) != "" {
+		w.last = strings.TrimSpace(line)
+	}
+
+	m := hfBarRegex.FindStringSubmatch(line)
+	// the bar counting the files fetched is not a byte count
+	if m == nil || strings.HasPrefix(m[1], "Fetching ") {
+		return
+	}
+
+	w.files[m[1]] = [2]int64{parseHFSize(m[2], m[3]), parseHFSize(m[4], m[5])}
+
+	var current, total int64
+	for _, f := range w.files {
+		current += f[0]
+		total += f[1]
+	}
+	w.progress(current, total)
+}
+
+// LastLine returns the last line of the output, the error of a failed download.
+func (w *HFProgressWriter) LastLine() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.last
+}
+
+// parseHFSize parses a size scaled by tqdm, in decimal units (Eg:- 4.97G).
+func parseHFSize(value, unit string) int64 {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+
+	exp := 0
+	if unit != "" {
+		exp = strings.Index("kMGTP", unit) + 1
+	}
+
+	return int64(v * math.Pow(hfUnitBase, float64(exp)))
+}
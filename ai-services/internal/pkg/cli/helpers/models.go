@@ -1,8 +1,13 @@
 package helpers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/containers/podman/v5/pkg/specgen"
@@ -10,15 +15,21 @@ import (
 	"github.com/project-ai-services/ai-services/assets"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
 	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+	"github.com/project-ai-services/ai-services/internal/pkg/image"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcache"
 	"github.com/project-ai-services/ai-services/internal/pkg/models"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelsource"
+	"github.com/project-ai-services/ai-services/internal/pkg/progress"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/podman"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 )
 
-func ListModels(template, appName string) ([]string, error) {
+// ListModels returns the models required by the enabled pod templates of the given application template.
+func ListModels(template, appName string, valuesFiles []string, argParams map[string]string) ([]string, error) {
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
-	tmpls, err := tp.LoadAllTemplates(template)
+	tmpls, err := tp.LoadEnabledPodTemplates(template, valuesFiles, argParams)
 	if err != nil {
 		return nil, fmt.Errorf("error loading templates for %s: %w", template, err)
 	}
@@ -36,7 +47,7 @@ func ListModels(template, appName string) ([]string, error) {
 
 	modelList := []string{}
 	for _, tmpl := range tmpls {
-		ps, err := tp.LoadPodTemplateWithValues(template, tmpl.Name(), appName, nil, nil)
+		ps, err := tp.LoadPodTemplateWithValues(template, tmpl.Name(), appName, valuesFiles, argParams)
 		if err != nil {
 			return nil, fmt.Errorf("error loading pod template: %w", err)
 		}
@@ -46,16 +57,205 @@ func ListModels(template, appName string) ([]string, error) {
 	return modelList, nil
 }
 
-func DownloadModel(model, targetDir string) error {
+// DownloadModels downloads the models of the application template to the target directory, vars.MaxParallelDownloads
+// of them at once, retrying the failed downloads. Each model is downloaded from the source of its annotation or of the
+// model sources config, the Hugging Face hub by default. The interrupted downloads resume from the partial files kept
+// in the model directory. The manifest of each model is recorded once it is downloaded.
+func DownloadModels(template string, models []string, targetDir string) error {
 	// check for target model directory, if not present create it
-	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-		err := os.MkdirAll(targetDir, os.ModePerm)
+	if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create target model directory: %w", err)
+	}
+
+	downloads, err := resolveModelSources(template, models)
+	if err != nil {
+		return err
+	}
+	names := slices.Sorted(maps.Keys(downloads))
+
+	tracker := progress.New(fmt.Sprintf("Downloading %d models:", len(names)))
+	items := make(map[string]*progress.Item, len(names))
+	for _, model := range names {
+		revision := ""
+		if downloads[model].model.Revision != "" {
+			revision = " at revision " + downloads[model].model.Revision
+		}
+		logger.Infof("Downloading model %s%s from %s to %s\n", model, revision, downloads[model].source, targetDir)
+		items[model] = tracker.Add(model)
+	}
+
+	tracker.Start()
+	err = utils.ForEachParallel(names, vars.MaxParallelDownloads, func(model string) error {
+		if err := downloadAndRecordModel(downloads[model], targetDir, items[model].Update); err != nil {
+			items[model].Fail()
+
+			return fmt.Errorf("failed to download model %s: %w", model, err)
+		}
+		items[model].Done()
+
+		return nil
+	})
+	tracker.Stop()
+
+	if err != nil {
+		return err
+	}
+
+	for _, model := range names {
+		logger.Infof("Model downloaded successfully: %s\n", model)
+	}
+
+	return nil
+}
+
+// modelDownload is a model to download, along with the source it is fetched from.
+type modelDownload struct {
+	model  modelsource.Model
+	source modelsource.Source
+}
+
+// ModelSources returns the sources the models of the application template are downloaded from, by model name.
+func ModelSources(template string, models []string) (map[string]modelsource.Source, error) {
+	downloads, err := resolveModelSources(template, models)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]modelsource.Source, len(downloads))
+	for name, download := range downloads {
+		sources[name] = download.source
+	}
+
+	return sources, nil
+}
+
+// resolveModelSources returns the sources the models are downloaded from, by model name.
+func resolveModelSources(template string, models []string) (map[string]modelDownload, error) {
+	toolImage, err := image.ResolveToolImage(template)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := modelsource.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	hf := func(_ context.Context, endpoint, model, revision, targetDir string, progress func(current, total int64)) error {
+		return downloadModel(toolImage, endpoint, model, revision, targetDir, progress)
+	}
+
+	downloads := make(map[string]modelDownload, len(models))
+	for _, spec := range models {
+		model, source, err := cfg.Resolve(spec, hf)
 		if err != nil {
-			return fmt.Errorf("failed to create target model directory: %w", err)
+			return nil, err
+		}
+
+		// a model is downloaded to a single directory, which holds a single revision of it
+		if other, ok := downloads[model.Name]; ok && other.model.Revision != model.Revision {
+			return nil, fmt.Errorf("model %s is pinned to both revision %q and revision %q", model.Name, other.model.Revision, model.Revision)
 		}
+		downloads[model.Name] = modelDownload{model: model, source: source}
 	}
-	logger.Infof("Downloading model %s to %s\n", model, targetDir)
 
+	return downloads, nil
+}
+
+// downloadAndRecordModel downloads the model and records its manifest along with its revision, the manifest of the
+// previous download being removed until then so that an interrupted download is not taken for a complete one.
+func downloadAndRecordModel(download modelDownload, targetDir string, progress func(current, total int64)) error {
+	model := download.model.Name
+	previous, err := modelcache.ReadManifest(targetDir, model)
+	if err != nil && !errors.Is(err, modelcache.ErrNoManifest) {
+		return err
+	}
+
+	if err := modelcache.RemoveManifest(targetDir, model); err != nil {
+		return err
+	}
+
+	return utils.Retry(vars.RetryCount, vars.RetryInterval, nil, func() error {
+		if err := download.source.Fetch(context.Background(), model, targetDir, progress); err != nil {
+			return err
+		}
+
+		_, err := modelcache.WriteManifest(targetDir, model, download.model.Revision, previous)
+
+		return err
+	})
+}
+
+// CheckModels checks that the models are present in the target directory, at the revisions they are pinned to, and
+// that none of their files is missing or truncated, against their manifests. The models without a manifest are only
+// checked for presence.
+func CheckModels(models []string, targetDir string) error {
+	var errs []error
+	for _, spec := range utils.UniqueSlice(models) {
+		model := modelsource.Name(spec)
+		if _, err := os.Stat(filepath.Join(targetDir, model)); err != nil {
+			errs = append(errs, fmt.Errorf("model %s is not present in %s, download it with 'ai-services application model download'", model, targetDir))
+
+			continue
+		}
+
+		if err := CheckModelRevision(spec, targetDir); err != nil {
+			errs = append(errs, fmt.Errorf("%w, download it again with 'ai-services application model download'", err))
+
+			continue
+		}
+
+		err := modelcache.Verify(targetDir, model, modelcache.VerifySize)
+		if errors.Is(err, modelcache.ErrNoManifest) {
+			logger.Warningf("Model %s has no manifest to check its files against, download it again to record one\n", model)
+
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckModelRevision checks that the model of the annotation, if pinned to a revision, was downloaded to the target
+// directory at that revision.
+func CheckModelRevision(spec, targetDir string) error {
+	model, err := modelsource.Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	return modelcache.CheckRevision(targetDir, model.Name, model.Revision)
+}
+
+// CheckDownloadedRevisions checks that the pinned models already present in the target directory were downloaded at
+// the revisions they are pinned to.
+func CheckDownloadedRevisions(models []string, targetDir string) error {
+	var errs []error
+	for _, spec := range utils.UniqueSlice(models) {
+		if _, err := os.Stat(filepath.Join(targetDir, modelsource.Name(spec))); err != nil {
+			continue
+		}
+
+		if err := CheckModelRevision(spec, targetDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DownloadModel downloads the model to the target directory.
+func DownloadModel(model, targetDir string) error {
+	return DownloadModels("", []string{model}, targetDir)
+}
+
+// downloadModel downloads the model with hf download run in the tool image, the progress parsed from its output.
+// The model is downloaded from the Hugging Face compatible endpoint if set, from the Hugging Face hub otherwise,
+// at the revision if set, the latest otherwise.
+func downloadModel(toolImage, endpoint, model, revision, targetDir string, progress func(current, total int64)) error {
 	// Get Podman client
 	runtimeClient, err := podman.NewPodmanClient()
 	if err != nil {
@@ -63,11 +263,10 @@ func DownloadModel(model, targetDir string) error {
 	}
 
 	// Create container spec
-	s := specgen.NewSpecGenerator(vars.ToolImage, false)
+	s := specgen.NewSpecGenerator(toolImage, false)
+	// hf download draws its progress bars on a terminal only
 	terminal := true
-	stdin := true
 	s.Terminal = &terminal
-	s.Stdin = &stdin
 	s.Command = []string{
 		"hf",
 		"download",
@@ -75,8 +274,14 @@ func DownloadModel(model, targetDir string) error {
 		"--local-dir",
 		fmt.Sprintf("/models/%s", model),
 	}
+	if revision != "" {
+		s.Command = append(s.Command, "--revision", revision)
+	}
 	rm := true
 	s.Remove = &rm
+	if endpoint != "" {
+		s.Env = map[string]string{"HF_ENDPOINT": endpoint}
+	}
 
 	// Convert mounts
 	s.Mounts = []spec.Mount{
@@ -89,16 +294,15 @@ func DownloadModel(model, targetDir string) error {
 	}
 
 	// Run container with spec
-	exitCode, err := runtimeClient.RunContainerWithSpec(s)
+	output := NewHFProgressWriter(progress)
+	exitCode, err := runtimeClient.RunContainerWithOutput(s, output)
 	if err != nil {
 		return fmt.Errorf("failed to run container: %w", err)
 	}
 
 	if exitCode != 0 {
-		return fmt.Errorf("model download failed with exit code %d", exitCode)
+		return fmt.Errorf("model download failed with exit code %d: %s", exitCode, output.LastLine())
 	}
 
-	logger.Infoln("Model downloaded successfully")
-
 	return nil
 }
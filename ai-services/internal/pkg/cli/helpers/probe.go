@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/containers/podman/v5/pkg/k8s.io/api/core/v1"
+	"github.com/containers/podman/v5/pkg/k8s.io/apimachinery/pkg/util/intstr"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
+)
+
+const (
+	defaultProbeTimeout = 1 * time.Second
+)
+
+// ProbeTarget holds everything needed to run a container readiness probe from the CLI.
+type ProbeTarget struct {
+	ContainerID string
+	// PodIP is used as host for HTTP and TCP probes which do not set one
+	PodIP string
+	// Ports are the container ports, used to resolve named probe ports
+	Ports []v1.ContainerPort
+	Probe *v1.Probe
+}
+
+// HasReadinessProbe returns true if the readiness probe declares one of the supported actions.
+func HasReadinessProbe(probe *v1.Probe) bool {
+	return probe != nil && (probe.HTTPGet != nil || probe.TCPSocket != nil || probe.Exec != nil)
+}
+
+// WaitForProbeReadiness runs the readiness probe until it succeeds successThreshold times in a row,
+// or the timeout is reached. The probe is first run after its initial delay.
+func WaitForProbeReadiness(runtime runtime.Runtime, target ProbeTarget, timeout time.Duration) error {
+	initialDelay := time.Duration(target.Probe.InitialDelaySeconds) * time.Second
+	if initialDelay > 0 {
+		logger.Infof("Waiting %s before running the readiness probe\n", initialDelay, logger.VerbosityLevelDebug)
+		time.Sleep(initialDelay)
+	}
+
+	successThreshold := max(int(target.Probe.SuccessThreshold), 1)
+	successes := 0
+
+	return pollUntil(timeout-initialDelay, "readiness probe to succeed", func() (bool, error) {
+		if err := RunReadinessProbe(runtime, target); err != nil {
+			logger.Infof("Readiness probe failed: %v\n", err, logger.VerbosityLevelDebug)
+			successes = 0
+
+			return false, nil
+		}
+		successes++
+
+		return successes >= successThreshold, nil
+	})
+}
+
+// RunReadinessProbe runs the readiness probe once and returns an error if the container is not ready.
+func RunReadinessProbe(runtime runtime.Runtime, target ProbeTarget) error {
+	probe := target.Probe
+
+	timeout := defaultProbeTimeout
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+
+	switch {
+	case probe.HTTPGet != nil:
+		return runHTTPProbe(target, timeout)
+	case probe.TCPSocket != nil:
+		return runTCPProbe(target, timeout)
+	case probe.Exec != nil:
+		return runExecProbe(runtime, target)
+	default:
+		return fmt.Errorf("readiness probe does not declare httpGet, tcpSocket or exec")
+	}
+}
+
+func runHTTPProbe(target ProbeTarget, timeout time.Duration) error {
+	action := target.Probe.HTTPGet
+
+	address, err := probeAddress(target, action.Host, action.Port)
+	if err != nil {
+		return err
+	}
+
+	scheme := strings.ToLower(string(action.Scheme))
+	if scheme == "" {
+		scheme = string(v1.URISchemeHTTP)
+	}
+
+	path := action.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s%s", scheme, address, path), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build http probe request: %w", err)
+	}
+
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	// like the kubelet, certificates are not verified for https probes
+	client := &http.Client{
+		Transport: &http.Transport{
+			//nolint:gosec // probes target the pod directly, mostly with self-signed certificates
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http probe failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http probe returned status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func runTCPProbe(target ProbeTarget, timeout time.Duration) error {
+	action := target.Probe.TCPSocket
+
+	address, err := probeAddress(target, action.Host, action.Port)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return fmt.Errorf("tcp probe failed: %w", err)
+	}
+
+	return conn.Close()
+}
+
+func runExecProbe(runtime runtime.Runtime, target ProbeTarget) error {
+	exitCode, err := runtime.ExecContainer(target.ContainerID, target.Probe.Exec.Command)
+	if err != nil {
+		return fmt.Errorf("exec probe failed: %w", err)
+	}
+
+	if exitCode != 0 {
+		return fmt.Errorf("exec probe exited with code: %d", exitCode)
+	}
+
+	return nil
+}
+
+// probeAddress returns the host:port to probe, defaulting the host to the pod IP and
+// resolving named ports against the container ports.
+func probeAddress(target ProbeTarget, host string, port intstr.IntOrString) (string, error) {
+	if host == "" {
+		host = target.PodIP
+	}
+	if host == "" {
+		return "", fmt.Errorf("no host set for the probe and the pod IP is unknown")
+	}
+
+	portNum, err := resolveProbePort(port, target.Ports)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(portNum)), nil
+}
+
+func resolveProbePort(port intstr.IntOrString, ports []v1.ContainerPort) (int, error) {
+	if port.Type == intstr.Int {
+		return port.IntValue(), nil
+	}
+
+	if portNum, err := strconv.Atoi(port.StrVal); err == nil {
+		return portNum, nil
+	}
+
+	for _, p := range ports {
+		if p.Name == port.StrVal {
+			return int(p.ContainerPort), nil
+		}
+	}
+
+	return 0, fmt.Errorf("probe port '%s' does not match any container port", port.StrVal)
+}
@@ -6,29 +6,55 @@ import (
 	"strings"
 	"time"
 
+	v1 "github.com/containers/podman/v5/pkg/k8s.io/api/core/v1"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
 	"github.com/project-ai-services/ai-services/internal/pkg/constants"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 	"github.com/project-ai-services/ai-services/internal/pkg/models"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/specs"
 )
 
 const (
-	containerCreationTimeout       = 5 * time.Minute
-	extraContainerReadinessTimeout = 2 * time.Minute
+	defaultContainerCreationTimeout = 5 * time.Minute
+	extraContainerReadinessTimeout  = 2 * time.Minute
 )
 
 // DeployPodAndReadinessCheck deploys a pod and performs readiness checks on its containers.
+// The timeouts come from the template metadata and can be overridden by the pod annotations,
+// unset timeouts fall back to the defaults.
 func DeployPodAndReadinessCheck(rt runtime.Runtime, podSpec *models.PodSpec,
-	podTemplateName string, body io.Reader, opts map[string]string) error {
+	podTemplateName string, body io.Reader, opts map[string]string, timeouts templates.Timeouts) error {
+	timeouts, err := ResolvePodTimeouts(podSpec, timeouts)
+	if err != nil {
+		return err
+	}
+
+	pods, err := DeployPod(rt, podTemplateName, body, opts)
+	if err != nil {
+		return err
+	}
+
+	return PodReadinessCheck(rt, podSpec, podTemplateName, pods, timeouts)
+}
+
+// DeployPod runs podman kube play for the rendered pod template and returns the created pods.
+func DeployPod(rt runtime.Runtime, podTemplateName string, body io.Reader, opts map[string]string) ([]types.Pod, error) {
 	pods, err := rt.CreatePod(body, opts)
 	if err != nil {
-		return fmt.Errorf("failed pod creation: %w", err)
+		return nil, fmt.Errorf("failed pod creation: %w", err)
 	}
 
 	logger.Infof("'%s': Successfully ran podman kube play\n", podTemplateName, logger.VerbosityLevelDebug)
 
+	return pods, nil
+}
+
+// PodReadinessCheck waits for all the containers of the given pods to be created and ready.
+// The timeouts are expected to be resolved with ResolvePodTimeouts.
+func PodReadinessCheck(rt runtime.Runtime, podSpec *models.PodSpec, podTemplateName string, pods []types.Pod, timeouts templates.Timeouts) error {
 	// ---- Pod Readiness Checks ----
 	for _, pod := range pods {
 		pInfo, err := rt.InspectPod(pod.ID)
@@ -41,13 +67,22 @@ func DeployPodAndReadinessCheck(rt runtime.Runtime, podSpec *models.PodSpec,
 		logger.Infof("'%s', '%s': Starting Pod Readiness check...\n", podTemplateName, podName)
 
 		// Step1: ---- Containers Creation Check ----
-		if err := doContainersCreationCheck(rt, podSpec, podTemplateName, pInfo.Name, pInfo.ID); err != nil {
+		if err := doContainersCreationCheck(rt, podSpec, podTemplateName, pInfo.Name, pInfo.ID, timeouts.ContainerCreation); err != nil {
 			return err
 		}
 
+		// refetch the pod info, as the containers might not have been created at the first inspect
+		pInfo, err = rt.InspectPod(pod.ID)
+		if err != nil {
+			return fmt.Errorf("failed to do pod inspect for podID: '%s' with error: %w", pod.ID, err)
+		}
+
 		// Step2: ---- Containers Readiness Check ----
 		for _, container := range pInfo.Containers {
-			if err := doContainerReadinessCheck(rt, podTemplateName, pInfo.Name, container.ID); err != nil {
+			if container.ID == pInfo.InfraContainerID {
+				continue
+			}
+			if err := doContainerReadinessCheck(rt, podSpec, pInfo, podTemplateName, container.ID, timeouts.Readiness); err != nil {
 				return err
 			}
 			logger.Infoln("-------")
@@ -61,14 +96,43 @@ func DeployPodAndReadinessCheck(rt runtime.Runtime, podSpec *models.PodSpec,
 	return nil
 }
 
-func doContainersCreationCheck(rt runtime.Runtime, podSpec *models.PodSpec, podTemplateName, podName, podID string) error {
+// ResolvePodTimeouts overrides the given timeouts with the ones set in the pod annotations,
+// and sets the default containers creation timeout if none is set.
+func ResolvePodTimeouts(podSpec *models.PodSpec, timeouts templates.Timeouts) (templates.Timeouts, error) {
+	podAnnotations := specs.FetchPodAnnotations(*podSpec)
+	overrides := map[string]*time.Duration{
+		constants.PodCreationTimeoutAnnotationKey:  &timeouts.ContainerCreation,
+		constants.PodReadinessTimeoutAnnotationKey: &timeouts.Readiness,
+	}
+
+	for annotation, timeout := range overrides {
+		val, ok := podAnnotations[annotation]
+		if !ok {
+			continue
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil || d <= 0 {
+			return timeouts, fmt.Errorf("invalid value '%s' for annotation '%s': must be a positive duration (Eg:- 10m)", val, annotation)
+		}
+		*timeout = d
+	}
+
+	if timeouts.ContainerCreation <= 0 {
+		timeouts.ContainerCreation = defaultContainerCreationTimeout
+	}
+
+	return timeouts, nil
+}
+
+func doContainersCreationCheck(rt runtime.Runtime, podSpec *models.PodSpec, podTemplateName, podName, podID string, timeout time.Duration) error {
 	logger.Infof("'%s', '%s': Performing Containers Creation check for pod...\n", podTemplateName, podName)
 
 	expectedContainerCount := len(specs.FetchContainerNames(*podSpec))
 
-	logger.Infof("'%s', '%s': Waiting for Containers Creation... Timeout set: %s\n", podTemplateName, podName, containerCreationTimeout)
+	logger.Infof("'%s', '%s': Waiting for Containers Creation... Timeout set: %s\n", podTemplateName, podName, timeout)
 	// wait for all containers for a given pod are created
-	if err := helpers.WaitForContainersCreation(rt, podID, expectedContainerCount, containerCreationTimeout); err != nil {
+	if err := helpers.WaitForContainersCreation(rt, podID, expectedContainerCount, timeout); err != nil {
 		return fmt.Errorf("containers creation check failed for pod: '%s' with error: %w", podName, err)
 	}
 
@@ -77,39 +141,94 @@ func doContainersCreationCheck(rt runtime.Runtime, podSpec *models.PodSpec, podT
 	return nil
 }
 
-func doContainerReadinessCheck(rt runtime.Runtime, podTemplateName, podName, containerID string) error {
+// doContainerReadinessCheck waits for the container healthcheck to report healthy. For containers without
+// a healthcheck, the readinessProbe declared for the container in the pod spec is run from the CLI instead.
+// If a readiness timeout is set it is used as is, else it is derived from the healthcheck start period
+// or the probe initial delay plus extraContainerReadinessTimeout.
+func doContainerReadinessCheck(rt runtime.Runtime, podSpec *models.PodSpec, pInfo *types.Pod,
+	podTemplateName, containerID string, readinessTimeout time.Duration) error {
 	cInfo, err := rt.InspectContainer(containerID)
 	if err != nil {
 		return fmt.Errorf("failed to do container inspect for containerID: '%s' with error: %w", containerID, err)
 	}
 
-	logger.Infof("'%s', '%s', '%s': Performing Container Readiness check...\n", podTemplateName, podName, cInfo.Name)
+	logger.Infof("'%s', '%s', '%s': Performing Container Readiness check...\n", podTemplateName, pInfo.Name, cInfo.Name)
 
-	// getting the Start Period set for a container
-	startPeriod, err := helpers.FetchContainerStartPeriod(rt, containerID)
-	if err != nil {
-		return fmt.Errorf("fetching container: '%s' start period failed: %w", cInfo.Name, err)
+	if cInfo.HasHealthcheck {
+		if readinessTimeout <= 0 {
+			// configure readiness timeout by appending start period with additional extra timeout
+			readinessTimeout = cInfo.HealthcheckStartPeriod + extraContainerReadinessTimeout
+		}
+
+		logger.Infof("'%s', '%s', '%s': Waiting for Container Readiness... Timeout set: %s\n", podTemplateName, pInfo.Name, cInfo.Name, readinessTimeout)
+
+		if err := helpers.WaitForContainerReadiness(rt, containerID, readinessTimeout); err != nil {
+			return fmt.Errorf("readiness check failed for container: '%s'!: %w", cInfo.Name, err)
+		}
+		logger.Infof("'%s', '%s', '%s': Readiness Check for the container is completed!\n", podTemplateName, pInfo.Name, cInfo.Name)
+
+		return nil
 	}
 
-	if startPeriod == -1 {
-		logger.Infof("No container health check is set for '%s'. Hence skipping readiness check\n", cInfo.Name, logger.VerbosityLevelDebug)
+	containerSpec := findContainerSpec(podSpec, pInfo.Name, cInfo.Name)
+	if containerSpec == nil || !helpers.HasReadinessProbe(containerSpec.ReadinessProbe) {
+		logger.Infof("No container health check or readiness probe is set for '%s'. Hence skipping readiness check\n", cInfo.Name, logger.VerbosityLevelDebug)
 
 		return nil
 	}
 
-	// configure readiness timeout by appending start period with additional extra timeout
-	readinessTimeout := startPeriod + extraContainerReadinessTimeout
+	if readinessTimeout <= 0 {
+		readinessTimeout = time.Duration(containerSpec.ReadinessProbe.InitialDelaySeconds)*time.Second + extraContainerReadinessTimeout
+	}
+
+	podIP, err := fetchPodIP(rt, pInfo)
+	if err != nil {
+		return err
+	}
+
+	target := helpers.ProbeTarget{
+		ContainerID: containerID,
+		PodIP:       podIP,
+		Ports:       containerSpec.Ports,
+		Probe:       containerSpec.ReadinessProbe,
+	}
+
+	logger.Infof("'%s', '%s', '%s': Waiting for Readiness Probe... Timeout set: %s\n", podTemplateName, pInfo.Name, cInfo.Name, readinessTimeout)
 
-	logger.Infof("'%s', '%s', '%s': Waiting for Container Readiness... Timeout set: %s\n", podTemplateName, podName, cInfo.Name, readinessTimeout)
+	if err := helpers.WaitForProbeReadiness(rt, target, readinessTimeout); err != nil {
+		return fmt.Errorf("readiness probe failed for container: '%s'!: %w", cInfo.Name, err)
+	}
+	logger.Infof("'%s', '%s', '%s': Readiness Probe for the container is completed!\n", podTemplateName, pInfo.Name, cInfo.Name)
 
-	if err := helpers.WaitForContainerReadiness(rt, containerID, readinessTimeout); err != nil {
-		return fmt.Errorf("readiness check failed for container: '%s'!: %w", cInfo.Name, err)
+	return nil
+}
+
+// findContainerSpec returns the pod spec of the container, podman names the containers as '<pod name>-<container name>'.
+func findContainerSpec(podSpec *models.PodSpec, podName, containerName string) *v1.Container {
+	name := strings.TrimPrefix(containerName, podName+"-")
+	for i := range podSpec.Spec.Containers {
+		if podSpec.Spec.Containers[i].Name == name {
+			return &podSpec.Spec.Containers[i]
+		}
 	}
-	logger.Infof("'%s', '%s', '%s': Readiness Check for the container is completed!\n", podTemplateName, podName, cInfo.Name)
 
 	return nil
 }
 
+// fetchPodIP returns the IP of the pod, which is held by its infra container.
+func fetchPodIP(rt runtime.Runtime, pInfo *types.Pod) (string, error) {
+	if pInfo.InfraContainerID == "" {
+		return "", nil
+	}
+
+	infra, err := rt.InspectContainer(pInfo.InfraContainerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to do infra container inspect for pod: '%s' with error: %w", pInfo.Name, err)
+	}
+
+	return infra.IPAddress, nil
+}
+
 // ConstructPodDeployOptions constructs pod deployment options from annotations.
 func ConstructPodDeployOptions(podAnnotations map[string]string) map[string]string {
 	podStart := checkForPodStartAnnotation(podAnnotations)
@@ -121,7 +240,7 @@ func ConstructPodDeployOptions(podAnnotations map[string]string) map[string]stri
 	}
 
 	// construct publish option
-	hostPortMappings := fetchHostPortMappingFromAnnotation(podAnnotations)
+	hostPortMappings := FetchHostPortMappingFromAnnotation(podAnnotations)
 	podDeployOptions["publish"] = ""
 
 	// loop over each of the hostPortMappings to construct the 'publish' option
@@ -153,7 +272,9 @@ func checkForPodStartAnnotation(podAnnotations map[string]string) string {
 	return ""
 }
 
-func fetchHostPortMappingFromAnnotation(podAnnotations map[string]string) map[string]string {
+// FetchHostPortMappingFromAnnotation returns the host port of each container port published
+// via the ai-services.io/ports annotation, an empty host port meaning a random one.
+func FetchHostPortMappingFromAnnotation(podAnnotations map[string]string) map[string]string {
 	// key -> containerPort and value -> hostPort
 	hostPortMapping := map[string]string{}
 
@@ -0,0 +1,124 @@
+package podman
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// PodDeployStatus represents the outcome of deploying a pod template.
+type PodDeployStatus string
+
+const (
+	PodDeployPending  PodDeployStatus = "Pending"
+	PodDeployDeployed PodDeployStatus = "Deployed"
+	PodDeployFailed   PodDeployStatus = "Failed"
+	PodDeploySkipped  PodDeployStatus = "Skipped"
+)
+
+type podDeployResult struct {
+	podTemplate string
+	err         error
+}
+
+// DeployPodGraph deploys the pod templates of the graph, starting each pod as soon as all of its
+// dependencies are deployed. At most maxParallelism pods are deployed at once, 0 means no limit.
+// Once a pod fails no new pods are started, the in-flight ones are awaited and a report is printed.
+func DeployPodGraph(graph *templates.PodGraph, maxParallelism int, deploy func(podTemplate string) error) error {
+	if maxParallelism <= 0 {
+		maxParallelism = len(graph.Order)
+	}
+
+	status := make(map[string]PodDeployStatus, len(graph.Order))
+	failures := map[string]error{}
+	pendingDeps := make(map[string]int, len(graph.Order))
+	ready := []string{}
+
+	for _, podTemplate := range graph.Order {
+		status[podTemplate] = PodDeployPending
+		pendingDeps[podTemplate] = len(graph.DependsOn[podTemplate])
+		if pendingDeps[podTemplate] == 0 {
+			ready = append(ready, podTemplate)
+		}
+	}
+
+	results := make(chan podDeployResult, len(graph.Order))
+	running := 0
+
+	for {
+		// start as many ready pods as allowed, unless a failure has already occurred
+		for len(failures) == 0 && len(ready) > 0 && running < maxParallelism {
+			podTemplate := ready[0]
+			ready = ready[1:]
+			running++
+
+			logger.Infof("'%s': Dependencies ready %v, starting deployment\n", podTemplate, graph.DependsOn[podTemplate])
+			go func(t string) {
+				results <- podDeployResult{podTemplate: t, err: deploy(t)}
+			}(podTemplate)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+
+		if result.err != nil {
+			status[result.podTemplate] = PodDeployFailed
+			failures[result.podTemplate] = result.err
+
+			continue
+		}
+
+		status[result.podTemplate] = PodDeployDeployed
+		for _, dependent := range graph.Dependents(result.podTemplate) {
+			pendingDeps[dependent]--
+			if pendingDeps[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	printPodDeployReport(graph, status, failures)
+
+	errs := make([]error, 0, len(failures))
+	for _, podTemplate := range graph.Order {
+		if err, ok := failures[podTemplate]; ok {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func printPodDeployReport(graph *templates.PodGraph, status map[string]PodDeployStatus, failures map[string]error) {
+	logger.Infoln("Deployment report:")
+
+	printer := utils.NewTableWriter()
+	defer printer.CloseTableWriter()
+
+	printer.SetHeaders("POD TEMPLATE", "STATUS", "DETAILS")
+
+	for _, podTemplate := range graph.Order {
+		details := ""
+		switch status[podTemplate] {
+		case PodDeployFailed:
+			details = failures[podTemplate].Error()
+		case PodDeployPending:
+			// pods never started because of a failure are reported as skipped
+			status[podTemplate] = PodDeploySkipped
+			details = fmt.Sprintf("not started due to an earlier failure (depends on %v)", graph.DependsOn[podTemplate])
+		}
+
+		printer.AppendRow(podTemplate, string(status[podTemplate]), details)
+	}
+}
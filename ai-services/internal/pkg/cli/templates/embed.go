@@ -192,6 +192,42 @@ func (e *embedTemplateProvider) LoadAllTemplates(app string) (map[string]*templa
 	return tmpls, err
 }
 
+// LoadEnabledPodTemplates loads all templates for a given application and drops the ones
+// whose 'enabled' condition in the runtime metadata evaluates to false for the provided values.
+func (e *embedTemplateProvider) LoadEnabledPodTemplates(app string, valuesFileOverrides []string, cliOverrides map[string]string) (map[string]*template.Template, error) {
+	tmpls, err := e.LoadAllTemplates(app)
+	if err != nil {
+		return nil, err
+	}
+
+	appMetadata, err := e.LoadMetadata(app, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the app metadata: %w", err)
+	}
+
+	// nothing to evaluate if none of the pods are conditional
+	if len(appMetadata.Pods) == 0 {
+		return tmpls, nil
+	}
+
+	values, err := e.LoadValues(app, valuesFileOverrides, cliOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load params for application: %w", err)
+	}
+
+	for name := range tmpls {
+		enabled, err := appMetadata.IsPodTemplateEnabled(name, values)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			delete(tmpls, name)
+		}
+	}
+
+	return tmpls, nil
+}
+
 // LoadPodTemplate loads and renders a pod template with the given parameters.
 func (e *embedTemplateProvider) LoadPodTemplate(app, file string, params any) (*models.PodSpec, error) {
 	path := e.buildPath(app, getRuntime(), "templates", file)
@@ -222,9 +258,21 @@ func (e *embedTemplateProvider) LoadPodTemplateWithValues(app, file, appName str
 	if err != nil {
 		return nil, fmt.Errorf("failed to load params for application: %w", err)
 	}
+
+	appMetadata, err := e.LoadMetadata(app, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedModels, err := appMetadata.ResolveModels(values)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build full params directly
 	params := map[string]any{
 		"Values":          values,
+		"Models":          resolvedModels,
 		"AppName":         appName,
 		"AppTemplateName": "",
 		"Version":         "",
@@ -0,0 +1,304 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcatalog"
+	"github.com/project-ai-services/ai-services/internal/pkg/utils"
+)
+
+// PodGraph is the dependency graph of the pod templates to be deployed.
+type PodGraph struct {
+	// Order holds the pod templates in the order they are declared in the metadata
+	Order []string
+	// DependsOn maps a pod template to the pod templates it depends on
+	DependsOn map[string][]string
+}
+
+// Dependents returns the pod templates which directly depend on the given pod template.
+func (g *PodGraph) Dependents(podTemplate string) []string {
+	dependents := []string{}
+	for _, name := range g.Order {
+		if slices.Contains(g.DependsOn[name], podTemplate) {
+			dependents = append(dependents, name)
+		}
+	}
+
+	return dependents
+}
+
+// Layers groups the pod templates by depth in the graph: the first layer holds the pod templates
+// without dependencies, and every other pod template sits one layer after its deepest dependency.
+// Pod templates of the same layer can be deployed in parallel.
+func (g *PodGraph) Layers() [][]string {
+	depth := map[string]int{}
+
+	var depthOf func(podTemplate string) int
+	depthOf = func(podTemplate string) int {
+		if d, ok := depth[podTemplate]; ok {
+			return d
+		}
+
+		d := 0
+		for _, dep := range g.DependsOn[podTemplate] {
+			d = max(d, depthOf(dep)+1)
+		}
+		depth[podTemplate] = d
+
+		return d
+	}
+
+	var layers [][]string
+	for _, podTemplate := range g.Order {
+		d := depthOf(podTemplate)
+		for len(layers) <= d {
+			layers = append(layers, []string{})
+		}
+		layers[d] = append(layers[d], podTemplate)
+	}
+
+	return layers
+}
+
+// PodMetadata returns the pod metadata for the given pod template, nil if not declared.
+func (m *AppMetadata) PodMetadata(podTemplate string) *PodMetadata {
+	for i := range m.Pods {
+		if m.Pods[i].Template == podTemplate {
+			return &m.Pods[i]
+		}
+	}
+
+	return nil
+}
+
+// PodTimeouts returns the timeouts for the given pod template, where the timeouts set on the pod
+// take precedence over the ones set for the whole template.
+func (m *AppMetadata) PodTimeouts(podTemplate string) Timeouts {
+	timeouts := m.Timeouts
+
+	pod := m.PodMetadata(podTemplate)
+	if pod == nil {
+		return timeouts
+	}
+
+	if pod.Timeouts.ContainerCreation > 0 {
+		timeouts.ContainerCreation = pod.Timeouts.ContainerCreation
+	}
+	if pod.Timeouts.Readiness > 0 {
+		timeouts.Readiness = pod.Timeouts.Readiness
+	}
+
+	return timeouts
+}
+
+// PodTemplates returns all the pod templates declared in the metadata, either in
+// podTemplateExecutions or in pods, in the order of declaration.
+func (m *AppMetadata) PodTemplates() []string {
+	podTemplates := utils.FlattenArray(m.PodTemplateExecutions)
+	for _, pod := range m.Pods {
+		if !slices.Contains(podTemplates, pod.Template) {
+			podTemplates = append(podTemplates, pod.Template)
+		}
+	}
+
+	return podTemplates
+}
+
+// IsPodTemplateEnabled evaluates the 'enabled' condition of the given pod template against the values.
+func (m *AppMetadata) IsPodTemplateEnabled(podTemplate string, values map[string]any) (bool, error) {
+	pod := m.PodMetadata(podTemplate)
+	if pod == nil || pod.Enabled == "" {
+		// pods without any condition are always deployed
+		return true, nil
+	}
+
+	val, ok := utils.GetNestedValue(values, pod.Enabled)
+	if !ok {
+		return false, fmt.Errorf("enabled condition '%s' for pod template '%s' does not match any value", pod.Enabled, podTemplate)
+	}
+
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case string:
+		// values passed via --params are always strings
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("value '%s' for '%s' is not a boolean", v, pod.Enabled)
+		}
+
+		return enabled, nil
+	default:
+		return false, fmt.Errorf("value for '%s' is not a boolean", pod.Enabled)
+	}
+}
+
+// ResolveModels returns the models set in the models.<role> values for the roles of the metadata, checked against
+// the model catalog for the accelerator serving them. The pod templates render the names of the models and their
+// Spyre cards from them.
+func (m *AppMetadata) ResolveModels(values map[string]any) (map[string]modelcatalog.Model, error) {
+	resolved := make(map[string]modelcatalog.Model, len(m.Models))
+	if len(m.Models) == 0 {
+		return resolved, nil
+	}
+
+	catalog, err := modelcatalog.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range slices.Sorted(maps.Keys(m.Models)) {
+		key := "models." + role
+		val, ok := utils.GetNestedValue(values, key)
+		spec, isString := val.(string)
+		if !ok || !isString || spec == "" {
+			return nil, fmt.Errorf("value '%s' must be set to the %s model", key, role)
+		}
+
+		model, err := catalog.Resolve(role, spec, m.Models[role])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for '%s': %w", key, err)
+		}
+		resolved[role] = model
+	}
+
+	return resolved, nil
+}
+
+// BuildPodGraph builds the dependency graph for the pod templates which are enabled for the given values.
+// Dependencies are taken from 'dependsOn' under pods, and for the pods not declaring it,
+// every pod in a podTemplateExecutions layer depends on all the pods of the previous layer.
+// A dependency on a disabled pod is replaced with the dependencies of that disabled pod.
+func (m *AppMetadata) BuildPodGraph(values map[string]any) (*PodGraph, error) {
+	podTemplates := m.PodTemplates()
+
+	dependsOn, err := m.declaredDependencies(podTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := detectCycle(podTemplates, dependsOn); err != nil {
+		return nil, err
+	}
+
+	enabled := map[string]bool{}
+	graph := &PodGraph{DependsOn: map[string][]string{}}
+
+	for _, podTemplate := range podTemplates {
+		isEnabled, err := m.IsPodTemplateEnabled(podTemplate, values)
+		if err != nil {
+			return nil, err
+		}
+		enabled[podTemplate] = isEnabled
+		if isEnabled {
+			graph.Order = append(graph.Order, podTemplate)
+		}
+	}
+
+	for _, podTemplate := range graph.Order {
+		graph.DependsOn[podTemplate] = resolveEnabledDependencies(podTemplate, dependsOn, enabled)
+	}
+
+	return graph, nil
+}
+
+// declaredDependencies returns the dependencies of each pod template as declared in the metadata.
+func (m *AppMetadata) declaredDependencies(podTemplates []string) (map[string][]string, error) {
+	dependsOn := map[string][]string{}
+
+	// shorthand: each layer depends on the previous layer
+	for i, layer := range m.PodTemplateExecutions {
+		for _, podTemplate := range layer {
+			if i > 0 {
+				dependsOn[podTemplate] = slices.Clone(m.PodTemplateExecutions[i-1])
+			}
+		}
+	}
+
+	// explicit dependencies take precedence over the layers
+	for _, pod := range m.Pods {
+		if pod.DependsOn == nil {
+			continue
+		}
+
+		for _, dep := range pod.DependsOn {
+			if !slices.Contains(podTemplates, dep) {
+				return nil, fmt.Errorf("pod template '%s' depends on '%s' which is not declared in the metadata", pod.Template, dep)
+			}
+		}
+		dependsOn[pod.Template] = slices.Clone(pod.DependsOn)
+	}
+
+	return dependsOn, nil
+}
+
+// detectCycle returns an error naming the pod templates in the cycle, if the dependencies contain one.
+func detectCycle(podTemplates []string, dependsOn map[string][]string) error {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(podTemplate string) error
+	visit = func(podTemplate string) error {
+		switch state[podTemplate] {
+		case done:
+			return nil
+		case inProgress:
+			// the cycle starts at the first occurrence of the pod template in the current path
+			start := slices.Index(path, podTemplate)
+			cycle := append(slices.Clone(path[start:]), podTemplate)
+
+			return fmt.Errorf("dependency cycle detected between pod templates: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[podTemplate] = inProgress
+		path = append(path, podTemplate)
+
+		for _, dep := range dependsOn[podTemplate] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[podTemplate] = done
+
+		return nil
+	}
+
+	for _, podTemplate := range podTemplates {
+		if state[podTemplate] == unvisited {
+			if err := visit(podTemplate); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveEnabledDependencies returns the enabled dependencies of a pod template,
+// walking through the dependencies of any disabled pod template in between.
+func resolveEnabledDependencies(podTemplate string, dependsOn map[string][]string, enabled map[string]bool) []string {
+	resolved := []string{}
+
+	for _, dep := range dependsOn[podTemplate] {
+		if enabled[dep] {
+			resolved = append(resolved, dep)
+
+			continue
+		}
+		resolved = append(resolved, resolveEnabledDependencies(dep, dependsOn, enabled)...)
+	}
+
+	return utils.UniqueSlice(resolved)
+}
@@ -6,6 +6,7 @@ import (
 
 	"helm.sh/helm/v4/pkg/chart"
 
+	"github.com/project-ai-services/ai-services/internal/pkg/modelcatalog"
 	"github.com/project-ai-services/ai-services/internal/pkg/models"
 )
 
@@ -14,8 +15,36 @@ type AppMetadata struct {
 	Description           string           `yaml:"description,omitempty"`
 	Hidden                bool             `yaml:"hidden,omitempty"`
 	Version               string           `yaml:"version,omitempty"`
-	PodTemplateExecutions [][]string       `yaml:"podTemplateExecutions"`
+	PodTemplateExecutions [][]string       `yaml:"podTemplateExecutions,omitempty"`
+	Pods                  []PodMetadata    `yaml:"pods,omitempty"`
+	MaxParallelism        int              `yaml:"maxParallelism,omitempty"`
+	Timeouts              Timeouts         `yaml:"timeouts,omitempty"`
 	Openshift             OpenshiftRuntime `yaml:"openshift,omitempty"`
+	// Models maps the roles of the models served by the pods (Eg:- instruct) to the accelerator serving them, spyre
+	// or cpu. The model of each role is set with the models.<role> value, and checked against the model catalog.
+	Models map[string]modelcatalog.Accelerator `yaml:"models,omitempty"`
+}
+
+// PodMetadata holds the deployment settings for a single pod template.
+type PodMetadata struct {
+	// Template is the pod template file name (Eg:- summarize-api.yaml.tmpl)
+	Template string `yaml:"template"`
+	// Enabled is the dotted values key deciding whether the pod is deployed (Eg:- summarize.enabled).
+	// If not set, the pod is always deployed.
+	Enabled string `yaml:"enabled,omitempty"`
+	// DependsOn lists the pod templates which must be deployed and ready before this pod is deployed.
+	// If not set, the dependencies are derived from the previous layer in podTemplateExecutions.
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// Timeouts overrides the template level timeouts for this pod.
+	Timeouts Timeouts `yaml:"timeouts,omitempty"`
+}
+
+// Timeouts holds the timeouts used while deploying pods, unset values fall back to the defaults.
+type Timeouts struct {
+	// ContainerCreation is the time to wait for all the containers of a pod to be created (Eg:- 5m)
+	ContainerCreation time.Duration `yaml:"containerCreation,omitempty"`
+	// Readiness is the time to wait for each container of a pod to become ready (Eg:- 10m)
+	Readiness time.Duration `yaml:"readiness,omitempty"`
 }
 
 type OpenshiftRuntime struct {
@@ -56,6 +85,8 @@ type Template interface {
 	ListApplicationTemplateValues(app string) (map[string]string, error)
 	// LoadAllTemplates loads all templates for a given application
 	LoadAllTemplates(app string) (map[string]*template.Template, error)
+	// LoadEnabledPodTemplates loads the templates for a given application which are enabled for the provided values
+	LoadEnabledPodTemplates(app string, valuesFileOverrides []string, cliOverrides map[string]string) (map[string]*template.Template, error)
 	// LoadPodTemplate loads and renders a pod template with the given parameters
 	LoadPodTemplate(app, file string, params any) (*models.PodSpec, error)
 	// LoadPodTemplateWithValues loads and renders a pod template with values from application
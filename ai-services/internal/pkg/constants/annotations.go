@@ -5,4 +5,9 @@ const (
 	ModelAnnotationKey       = "ai-services.io/model"
 	PodStartAnnotationkey    = "ai-services.io/start"
 	PodPortsAnnotationKey    = "ai-services.io/ports"
+
+	// PodCreationTimeoutAnnotationKey overrides the containers creation timeout for a pod (Eg:- 10m).
+	PodCreationTimeoutAnnotationKey = "ai-services.io/creation-timeout"
+	// PodReadinessTimeoutAnnotationKey overrides the containers readiness timeout for a pod (Eg:- 15m).
+	PodReadinessTimeoutAnnotationKey = "ai-services.io/readiness-timeout"
 )
@@ -1,19 +1,39 @@
 package helm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"helm.sh/helm/v4/pkg/action"
 	"helm.sh/helm/v4/pkg/chart"
 	"helm.sh/helm/v4/pkg/cli"
 	"helm.sh/helm/v4/pkg/kube"
+	helmrelease "helm.sh/helm/v4/pkg/release"
 	"helm.sh/helm/v4/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/registries"
 )
 
+// mirrorPostRenderer rewrites the image references of the rendered manifests to the configured registry mirrors,
+// the references set in the values as well as the ones hard-coded in the templates.
+type mirrorPostRenderer struct{}
+
+func (mirrorPostRenderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	registriesConfig, err := registries.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(registriesConfig.RewriteManifest(renderedManifests.Bytes())), nil
+}
+
 type Helm struct {
 	namespace    string
 	actionConfig *action.Configuration
@@ -44,6 +64,41 @@ func NewHelm(namespace string) (*Helm, error) {
 	}, nil
 }
 
+// Template renders the chart locally without interacting with the cluster, like 'helm template',
+// and returns the rendered manifests, hooks included.
+func Template(release, namespace string, chart chart.Charter, values map[string]any) (string, error) {
+	templateClient := action.NewInstall(action.NewConfiguration())
+	templateClient.ReleaseName = release
+	templateClient.Namespace = namespace
+	templateClient.DryRunStrategy = action.DryRunClient
+	// skip the check on the release name being already in use
+	templateClient.Replace = true
+	templateClient.PostRenderer = mirrorPostRenderer{}
+
+	rel, err := templateClient.Run(chart, values)
+	if err != nil {
+		return "", fmt.Errorf("Template failed: %w", err)
+	}
+
+	accessor, err := helmrelease.NewAccessor(rel)
+	if err != nil {
+		return "", fmt.Errorf("Template failed: %w", err)
+	}
+
+	var manifests strings.Builder
+	fmt.Fprintln(&manifests, strings.TrimSpace(accessor.Manifest()))
+
+	for _, hook := range accessor.Hooks() {
+		hookAccessor, err := helmrelease.NewHookAccessor(hook)
+		if err != nil {
+			return "", fmt.Errorf("Template failed: %w", err)
+		}
+		fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", hookAccessor.Path(), hookAccessor.Manifest())
+	}
+
+	return manifests.String(), nil
+}
+
 type InstallOpts struct {
 	Values  map[string]any
 	Timeout time.Duration
@@ -57,6 +112,7 @@ func (h *Helm) Install(release string, chart chart.Charter, opts *InstallOpts) e
 	installClient.CreateNamespace = true
 	installClient.WaitStrategy = kube.StatusWatcherStrategy
 	installClient.Timeout = opts.Timeout
+	installClient.PostRenderer = mirrorPostRenderer{}
 
 	// Perform helm install
 	_, err := installClient.Run(chart, opts.Values)
@@ -81,6 +137,7 @@ func (h *Helm) Upgrade(release string, chart chart.Charter, opts *UpgradeOpts) e
 	upgradeClient.Timeout = opts.Timeout
 	upgradeClient.ForceConflicts = true
 	upgradeClient.RollbackOnFailure = true
+	upgradeClient.PostRenderer = mirrorPostRenderer{}
 
 	// Perform helm upgrade
 	_, err := upgradeClient.Run(release, chart, opts.Values)
@@ -130,3 +187,71 @@ func (h *Helm) Uninstall(release string, opts *UninstallOpts) error {
 
 	return nil
 }
+
+// GetValues returns the values the release is deployed with: the user supplied values,
+// or the values computed with the defaults of the chart if all is set.
+func (h *Helm) GetValues(release string, all bool) (map[string]any, error) {
+	client := action.NewGetValues(h.actionConfig)
+	client.AllValues = all
+
+	values, err := client.Run(release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values of release '%s': %w", release, err)
+	}
+
+	return values, nil
+}
+
+// ReleaseObject is an object of the manifest of a release, along with the object live in the cluster.
+type ReleaseObject struct {
+	Kind string
+	Name string
+	// Manifest is the object as rendered in the manifest of the release
+	Manifest map[string]any
+	// Live is the object as it is in the cluster, nil if it does not exist
+	Live map[string]any
+}
+
+// ReleaseObjects returns the objects of the manifest of the deployed revision of the release, along with their live state.
+func (h *Helm) ReleaseObjects(release string) ([]ReleaseObject, error) {
+	rel, err := action.NewGet(h.actionConfig).Run(release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release '%s': %w", release, err)
+	}
+
+	accessor, err := helmrelease.NewAccessor(rel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release '%s': %w", release, err)
+	}
+
+	resources, err := h.actionConfig.KubeClient.Build(strings.NewReader(accessor.Manifest()), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the objects of release '%s': %w", release, err)
+	}
+
+	objects := make([]ReleaseObject, 0, len(resources))
+	for _, info := range resources {
+		obj := ReleaseObject{Kind: info.Mapping.GroupVersionKind.Kind, Name: info.Name}
+		if obj.Manifest, err = runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object); err != nil {
+			return nil, fmt.Errorf("failed to convert %s '%s': %w", obj.Kind, obj.Name, err)
+		}
+
+		// Get replaces the object of the info with the one live in the cluster
+		if err := info.Get(); err != nil {
+			if apierrors.IsNotFound(err) {
+				objects = append(objects, obj)
+
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get %s '%s': %w", obj.Kind, obj.Name, err)
+		}
+
+		if obj.Live, err = runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object); err != nil {
+			return nil, fmt.Errorf("failed to convert %s '%s': %w", obj.Kind, obj.Name, err)
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
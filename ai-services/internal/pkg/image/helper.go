@@ -1,26 +1,58 @@
 package image
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/progress"
+	"github.com/project-ai-services/ai-services/internal/pkg/registries"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
 )
 
-// pullImageFromRegistry pulls the required images from registry.
+// pullImageFromRegistry pulls the required images from registry, vars.MaxParallelDownloads of them at once.
 func pullImageFromRegistry(runtime runtime.Runtime, images []string) error {
+	tracker := progress.New(fmt.Sprintf("Downloading %d images:", len(images)))
+	items := make(map[string]*progress.Item, len(images))
 	for _, image := range images {
-		logger.Infoln("Downloading image: " + image + "...")
-		if err := utils.Retry(vars.RetryCount, vars.RetryInterval, nil, func() error {
-			return runtime.PullImage(image)
-		}); err != nil {
+		items[image] = tracker.Add(image)
+	}
+
+	tracker.Start()
+	err := utils.ForEachParallel(images, vars.MaxParallelDownloads, func(image string) error {
+		if err := pullImage(runtime, image, items[image]); err != nil {
+			items[image].Fail()
+
 			return fmt.Errorf("failed to download image: %w", err)
 		}
+		items[image].Done()
+
+		return nil
+	})
+	tracker.Stop()
+
+	return err
+}
+
+// pullImage pulls the image with the pull options of its registry, retrying the failed pulls but the failed logins.
+func pullImage(runtime runtime.Runtime, image string, item *progress.Item) error {
+	opts, err := registries.PullOptions(image)
+	if err != nil {
+		return err
 	}
+	opts.Progress = item.Update
+
+	return utils.Retry(vars.RetryCount, vars.RetryInterval, nil, func() error {
+		err := runtime.PullImage(image, opts)
+		if errors.Is(err, types.ErrUnauthorized) {
+			return utils.Permanent(fmt.Errorf("%w, log in to the registry with podman login or set the credentials in %s",
+				err, vars.RegistriesConfig))
+		}
 
-	return nil
+		return err
+	})
 }
 
 // fetchImagesNotFound returns list of images which are not present locally.
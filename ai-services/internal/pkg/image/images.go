@@ -1,12 +1,17 @@
 package image
 
 import (
+	"context"
 	"fmt"
 	"slices"
 
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+
 	"github.com/project-ai-services/ai-services/assets"
 	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/registries"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
 	"github.com/project-ai-services/ai-services/internal/pkg/utils"
 	"github.com/project-ai-services/ai-services/internal/pkg/vars"
@@ -31,10 +36,63 @@ type Images struct {
 	Runtime     runtime.Runtime
 	App         string
 	AppTemplate string
+	// ValuesFiles and ArgParams are the value overrides used to decide which pods are enabled
+	ValuesFiles []string
+	ArgParams   map[string]string
+	// IgnoreLock lists the images without the digests of the lock file of the template, to generate it again
+	IgnoreLock bool
+	// Verify, if set, verifies the digests and the signatures of the images once they are pulled
+	Verify *VerifyOptions
+}
+
+// Ref is an image reference of an application template, along with the reference it is rewritten to
+// by the configured registry mirrors, and the digest it is pinned to.
+type Ref struct {
+	Original  string
+	Rewritten string
+	// Digest is the digest the image is pinned to by the template or by the lock file of the template, empty if unpinned
+	Digest string
 }
 
-// ListImages returns the list of images required for the application template.
+// Resolved returns the reference the image is pulled and run with: rewritten to the registry mirrors,
+// and pinned to its digest. The tag of a pinned reference is dropped, as podman drops it on pull.
+func (r Ref) Resolved() string {
+	if r.Digest == "" {
+		return r.Rewritten
+	}
+
+	named, err := reference.ParseNormalizedNamed(r.Rewritten)
+	if err != nil {
+		return r.Rewritten
+	}
+
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), digest.Digest(r.Digest))
+	if err != nil {
+		return r.Rewritten
+	}
+
+	return pinned.String()
+}
+
+// ListImages returns the list of images required for the application template, rewritten to the configured registry mirrors
+// and pinned to their digests.
 func (img *Images) ListImages() ([]string, error) {
+	refs, err := img.ListImageRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		images = append(images, ref.Resolved())
+	}
+
+	return utils.UniqueSlice(images), nil
+}
+
+// ListImageRefs returns the references of the images required for the application template, as set in the template
+// and as rewritten to the configured registry mirrors, along with the digests they are pinned to.
+func (img *Images) ListImageRefs() ([]Ref, error) {
 	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
 
 	// Fetch list of app templates
@@ -46,8 +104,8 @@ func (img *Images) ListImages() ([]string, error) {
 		return nil, fmt.Errorf("provided template name is wrong. Please provide a valid template name")
 	}
 
-	// Load all the pod templates for given template
-	tmpls, err := tp.LoadAllTemplates(img.AppTemplate)
+	// Load all the enabled pod templates for given template
+	tmpls, err := tp.LoadEnabledPodTemplates(img.AppTemplate, img.ValuesFiles, img.ArgParams)
 	if err != nil {
 		return nil, fmt.Errorf("error loading templates for %s: %w", img.AppTemplate, err)
 	}
@@ -59,7 +117,7 @@ func (img *Images) ListImages() ([]string, error) {
 
 	// Fetch all the images required for the given template by looping over each of the pod template files
 	for _, tmpl := range tmpls {
-		ps, err := tp.LoadPodTemplateWithValues(img.AppTemplate, tmpl.Name(), img.App, nil, nil)
+		ps, err := tp.LoadPodTemplateWithValues(img.AppTemplate, tmpl.Name(), img.App, img.ValuesFiles, img.ArgParams)
 		if err != nil {
 			return nil, fmt.Errorf("error loading pod template: %w", err)
 		}
@@ -68,27 +126,67 @@ func (img *Images) ListImages() ([]string, error) {
 		}
 	}
 
-	return utils.UniqueSlice(images), nil
+	registriesConfig, err := registries.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := img.lock()
+	if err != nil {
+		return nil, err
+	}
+
+	images = utils.UniqueSlice(images)
+	refs := make([]Ref, 0, len(images))
+	for _, image := range images {
+		digest := templateDigest(image)
+		if digest == "" {
+			digest = lock.Digest(image)
+		}
+		refs = append(refs, Ref{Original: image, Rewritten: registriesConfig.Rewrite(image), Digest: digest})
+	}
+
+	return refs, nil
 }
 
-// Run executes the image pull policy.
+// lock returns the lock file of the template, nil if the template has none or if it is ignored.
+func (img *Images) lock() (*LockFile, error) {
+	if img.IgnoreLock {
+		return nil, nil
+	}
+
+	return ReadLock(LockPath(img.AppTemplate))
+}
+
+// Run executes the image pull policy, and verifies the images if requested.
 func (img *Images) Run(policy ImagePullPolicy) error {
 	// Fetch all images required for the template
-	images, err := img.ListImages()
+	refs, err := img.ListImageRefs()
 	if err != nil {
 		return fmt.Errorf("failed to list container images for app %s template %s: %w", img.App, img.AppTemplate, err)
 	}
 
+	images := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		images = append(images, ref.Resolved())
+	}
+
 	switch policy {
 	case PullAlways:
-		return img.always(images)
+		err = img.always(images)
 	case PullIfNotPresent:
-		return img.ifNotPresent(images)
+		err = img.ifNotPresent(images)
 	case PullNever:
-		return img.never(images)
+		err = img.never(images)
 	default:
-		return fmt.Errorf("unsupported policy: %s", policy)
+		err = fmt.Errorf("unsupported policy: %s", policy)
 	}
+
+	if err != nil || img.Verify == nil {
+		return err
+	}
+
+	return img.verify(context.Background(), refs)
 }
 
 // always -> pulls all the images for a given app template.
@@ -0,0 +1,194 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/distribution/reference"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/manifest"
+	imageTypes "go.podman.io/image/v5/types"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/templates"
+	"github.com/project-ai-services/ai-services/internal/pkg/registries"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+const (
+	lockDirPermissions  = 0o755
+	lockFilePermissions = 0o644
+)
+
+// LockFile pins the images of an application template to the digests their references resolved to when it was
+// generated, so that the same images are pulled and run until it is generated again.
+type LockFile struct {
+	Template        string        `yaml:"template"`
+	TemplateVersion string        `yaml:"templateVersion"`
+	CreatedAt       time.Time     `yaml:"createdAt"`
+	Images          []LockedImage `yaml:"images"`
+}
+
+// LockedImage is an image reference of the template along with the digest it is pinned to.
+type LockedImage struct {
+	// Image is the reference as set in the template, before it is rewritten to the registry mirrors
+	Image  string `yaml:"image"`
+	Digest string `yaml:"digest"`
+}
+
+// LockPath returns the path of the lock file of the application template.
+func LockPath(template string) string {
+	return filepath.Join(vars.ImageLockDirectory, template+".lock.yaml")
+}
+
+// ReadLock returns the lock file at the path, nil if it does not exist.
+func ReadLock(path string) (*LockFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image lock file: %w", err)
+	}
+
+	lock := &LockFile{}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse image lock file %s: %w", path, err)
+	}
+
+	return lock, nil
+}
+
+// Write writes the lock file to the path.
+func (l *LockFile) Write(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal image lock file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), lockDirPermissions); err != nil {
+		return fmt.Errorf("failed to create image lock directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, lockFilePermissions); err != nil {
+		return fmt.Errorf("failed to write image lock file: %w", err)
+	}
+
+	return nil
+}
+
+// Digest returns the digest the image is pinned to, empty if the image is not locked.
+func (l *LockFile) Digest(image string) string {
+	if l == nil {
+		return ""
+	}
+
+	for _, locked := range l.Images {
+		if locked.Image == image {
+			return locked.Digest
+		}
+	}
+
+	return ""
+}
+
+// Lock resolves the references of the images of the template to their digests, and returns the lock file pinning them.
+// The images pinned to a digest by the template keep it.
+func (img *Images) Lock(ctx context.Context) (*LockFile, error) {
+	tp := templates.NewEmbedTemplateProvider(&assets.ApplicationFS)
+	appMetadata, err := tp.LoadMetadata(img.AppTemplate, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the app metadata: %w", err)
+	}
+
+	// the digests are resolved again, and not read from the current lock file
+	unlocked := *img
+	unlocked.IgnoreLock = true
+	refs, err := unlocked.ListImageRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &LockFile{Template: img.AppTemplate, TemplateVersion: appMetadata.Version, CreatedAt: time.Now().UTC()}
+	for _, ref := range refs {
+		digest := ref.Digest
+		if digest == "" {
+			if digest, err = resolveDigest(ctx, ref.Rewritten); err != nil {
+				return nil, err
+			}
+		}
+		lock.Images = append(lock.Images, LockedImage{Image: ref.Original, Digest: digest})
+	}
+
+	return lock, nil
+}
+
+// templateDigest returns the digest the reference is pinned to by the template (Eg:- icr.io/x/rag:v1@sha256:...), empty if none.
+func templateDigest(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+
+	if digested, ok := named.(reference.Digested); ok {
+		return digested.Digest().String()
+	}
+
+	return ""
+}
+
+// resolveDigest returns the digest of the manifest the reference points to in the registry, the digest of the
+// manifest list for multi-arch images, without pulling the image.
+func resolveDigest(ctx context.Context, ref string) (string, error) {
+	imgRef, err := docker.ParseReference("//" + ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %s: %w", ref, err)
+	}
+
+	sys, err := systemContext(ref)
+	if err != nil {
+		return "", err
+	}
+
+	src, err := imgRef.NewImageSource(ctx, sys)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the digest of image %s: %w", ref, err)
+	}
+	defer func() { _ = src.Close() }()
+
+	blob, _, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the digest of image %s: %w", ref, err)
+	}
+
+	digest, err := manifest.Digest(blob)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the digest of image %s: %w", ref, err)
+	}
+
+	return digest.String(), nil
+}
+
+// systemContext returns the context the registry of the reference is reached with, the credentials and the TLS
+// settings of the registries config.
+func systemContext(ref string) (*imageTypes.SystemContext, error) {
+	opts, err := registries.PullOptions(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	sys := &imageTypes.SystemContext{AuthFilePath: opts.AuthFile}
+	if opts.Username != "" {
+		sys.DockerAuthConfig = &imageTypes.DockerAuthConfig{Username: opts.Username, Password: opts.Password}
+	}
+	if opts.TLSVerify != nil {
+		sys.DockerInsecureSkipTLSVerify = imageTypes.NewOptionalBool(!*opts.TLSVerify)
+	}
+
+	return sys, nil
+}
@@ -0,0 +1,55 @@
+package image
+
+import (
+	"github.com/project-ai-services/ai-services/internal/pkg/registries"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+// NewResolver returns the function resolving the image references of the application template to the references
+// they are pulled and run with: rewritten to the registry mirrors and pinned to the digests of the template or of
+// its lock file.
+func NewResolver(template string) (func(ref string) string, error) {
+	registriesConfig, err := registries.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := ReadLock(LockPath(template))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ref string) string {
+		digest := templateDigest(ref)
+		if digest == "" {
+			digest = lock.Digest(ref)
+		}
+
+		return Ref{Original: ref, Rewritten: registriesConfig.Rewrite(ref), Digest: digest}.Resolved()
+	}, nil
+}
+
+// ResolveManifest resolves the references of the image fields of the rendered manifests of the application template.
+func ResolveManifest(template string, manifest []byte) ([]byte, error) {
+	resolve, err := NewResolver(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return registries.RewriteImageFields(manifest, resolve), nil
+}
+
+// ResolveToolImage returns the reference the tool image is run with for the application template,
+// the template being empty for the tool image run outside of a template.
+func ResolveToolImage(template string) (string, error) {
+	if template == "" {
+		return registries.RewriteImage(vars.ToolImage)
+	}
+
+	resolve, err := NewResolver(template)
+	if err != nil {
+		return "", err
+	}
+
+	return resolve(vars.ToolImage), nil
+}
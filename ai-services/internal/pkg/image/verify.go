@@ -0,0 +1,147 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.podman.io/image/v5/docker"
+	imageLib "go.podman.io/image/v5/image"
+	"go.podman.io/image/v5/signature"
+)
+
+const (
+	// sigstoreRegistriesConfig enables the lookup of the sigstore signatures attached to the images in the registries,
+	// where cosign stores them.
+	sigstoreRegistriesConfig    = "default-docker:\n  use-sigstore-attachments: true\n"
+	registriesConfigPermissions = 0o644
+)
+
+// VerifyOptions holds how the images are verified before they are used. The signatures are verified
+// with the public key if set, else with the containers policy.
+type VerifyOptions struct {
+	// PolicyPath is the path of the containers policy.json
+	PolicyPath string
+	// KeyPath is the path of the sigstore (cosign) public key the images must be signed with
+	KeyPath string
+}
+
+// verify checks that every image is pinned to a digest, is present locally with that digest,
+// and is signed as required.
+func (img *Images) verify(ctx context.Context, refs []Ref) error {
+	images := make([]string, 0, len(refs))
+	var errs []error
+	for _, ref := range refs {
+		if ref.Digest == "" {
+			errs = append(errs, fmt.Errorf("image %s is not pinned to a digest, pin it in the template or run `ai-services application image lock -t %s`",
+				ref.Original, img.AppTemplate))
+
+			continue
+		}
+		images = append(images, ref.Resolved())
+	}
+
+	notFound, err := fetchImagesNotFound(img.Runtime, images)
+	if err != nil {
+		return err
+	}
+	for _, image := range notFound {
+		errs = append(errs, fmt.Errorf("image %s is not present locally with the pinned digest", image))
+	}
+
+	if err := img.Verify.verifySignatures(ctx, images); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("image verification failed: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// verifySignatures verifies the signatures of the images against the policy. The images being pinned to their digests,
+// the signatures of the manifests in the registries are those of the images pulled.
+func (o *VerifyOptions) verifySignatures(ctx context.Context, images []string) error {
+	policy, registriesDir, err := o.policy()
+	if err != nil {
+		return err
+	}
+	if registriesDir != "" {
+		defer func() { _ = os.RemoveAll(registriesDir) }()
+	}
+
+	pc, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return fmt.Errorf("failed to create the signature policy context: %w", err)
+	}
+	defer func() { _ = pc.Destroy() }()
+
+	var errs []error
+	for _, image := range images {
+		if err := verifySignature(ctx, pc, image, registriesDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// policy returns the policy the images are verified against, along with the registries.d directory
+// enabling the sigstore attachments when verified with a key.
+func (o *VerifyOptions) policy() (*signature.Policy, string, error) {
+	if o.KeyPath == "" {
+		policy, err := signature.NewPolicyFromFile(o.PolicyPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read the signature policy: %w", err)
+		}
+
+		return policy, "", nil
+	}
+
+	requirement, err := signature.NewPRSigstoreSignedKeyPath(o.KeyPath, signature.NewPRMMatchRepoDigestOrExact())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the signature key: %w", err)
+	}
+
+	registriesDir, err := os.MkdirTemp("", "ai-services-registries.d-")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create the registries.d directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(registriesDir, "default.yaml"), []byte(sigstoreRegistriesConfig), registriesConfigPermissions); err != nil {
+		_ = os.RemoveAll(registriesDir)
+
+		return nil, "", fmt.Errorf("failed to write the registries.d config: %w", err)
+	}
+
+	return &signature.Policy{Default: signature.PolicyRequirements{requirement}}, registriesDir, nil
+}
+
+func verifySignature(ctx context.Context, pc *signature.PolicyContext, image, registriesDir string) error {
+	imgRef, err := docker.ParseReference("//" + image)
+	if err != nil {
+		return fmt.Errorf("invalid image reference %s: %w", image, err)
+	}
+
+	sys, err := systemContext(image)
+	if err != nil {
+		return err
+	}
+	if registriesDir != "" {
+		sys.RegistriesDirPath = registriesDir
+	}
+
+	src, err := imgRef.NewImageSource(ctx, sys)
+	if err != nil {
+		return fmt.Errorf("failed to reach image %s to verify its signature: %w", image, err)
+	}
+	defer func() { _ = src.Close() }()
+
+	if allowed, err := pc.IsRunningImageAllowed(ctx, imageLib.UnparsedInstance(src, nil)); !allowed {
+		return fmt.Errorf("signature verification failed for image %s: %w", image, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package modelcache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelsource"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+)
+
+// Model is a model downloaded to the local model directory.
+type Model struct {
+	// Name is the Hugging Face repository of the model (Eg:- ibm-granite/granite-3.3-8b-instruct)
+	Name string
+	// Revision is the revision the model was pinned to when downloaded, empty for the latest revision or if unknown
+	Revision string
+	Size     int64
+	// LastUsed is the last access time of the files of the model, as precise as the relatime mount option allows
+	LastUsed time.Time
+	// Apps are the applications whose pods reference the model
+	Apps []string
+}
+
+// List returns the models of the model directory, laid out as <dir>/<org>/<name>. The hidden entries,
+// such as the staging directories of the bundles, are skipped.
+func List(dir string) ([]Model, error) {
+	orgs, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model directory: %w", err)
+	}
+
+	var models []Model
+	for _, org := range orgs {
+		if !org.IsDir() || isHidden(org.Name()) {
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Join(dir, org.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read model directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || isHidden(entry.Name()) {
+				continue
+			}
+
+			model := Model{Name: org.Name() + "/" + entry.Name()}
+			if model.Size, model.LastUsed, err = usage(filepath.Join(dir, model.Name)); err != nil {
+				return nil, err
+			}
+			if manifest, err := ReadManifest(dir, model.Name); err == nil {
+				model.Revision = manifest.Revision
+			}
+			models = append(models, model)
+		}
+	}
+
+	return models, nil
+}
+
+// Remove removes the model from the model directory, along with its organization directory once empty.
+func Remove(dir, model string) error {
+	models, err := List(dir)
+	if err != nil {
+		return err
+	}
+
+	// only the models of the directory are removed, which also rules out the paths escaping it
+	if !slices.ContainsFunc(models, func(m Model) bool { return m.Name == model }) {
+		return fmt.Errorf("model %s is not present in %s", model, dir)
+	}
+
+	if err := os.RemoveAll(filepath.Join(dir, model)); err != nil {
+		return fmt.Errorf("failed to remove model %s: %w", model, err)
+	}
+
+	// the organization directory is kept if other models are left in it
+	_ = os.Remove(filepath.Dir(filepath.Join(dir, model)))
+
+	return nil
+}
+
+// References returns the applications referencing each model, from the ai-services.io/model* annotations
+// of the containers of their pods, running or not.
+func References(rt runtime.Runtime) (map[string][]string, error) {
+	pods, err := rt.ListPods(map[string][]string{
+		"label": {constants.ApplicationAnnotationKey},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	refs := map[string][]string{}
+	for _, pod := range pods {
+		app := pod.Labels[constants.ApplicationAnnotationKey]
+		// the annotations of the pod are set on each of its containers
+		idx := slices.IndexFunc(pod.Containers, func(c types.Container) bool { return c.ID != pod.InfraContainerID })
+		if idx < 0 {
+			continue
+		}
+
+		container, err := rt.InspectContainer(pod.Containers[idx].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container %s: %w", pod.Containers[idx].Name, err)
+		}
+
+		for key, spec := range container.Annotations {
+			model := modelsource.Name(spec)
+			if strings.HasPrefix(key, constants.ModelAnnotationKey) && !slices.Contains(refs[model], app) {
+				refs[model] = append(refs[model], app)
+			}
+		}
+	}
+
+	for model := range refs {
+		slices.Sort(refs[model])
+	}
+
+	return refs, nil
+}
+
+// usage returns the size of the files of the directory, and the last time any of them was accessed.
+func usage(dir string) (int64, time.Time, error) {
+	var size int64
+	var lastUsed time.Time
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+		if used := accessTime(info); used.After(lastUsed) {
+			lastUsed = used
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to read model directory %s: %w", dir, err)
+	}
+
+	return size, lastUsed, nil
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
@@ -0,0 +1,16 @@
+package modelcache
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the access time of the file, its modification time where the access time is not available.
+func accessTime(info fs.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Atim.Sec, stat.Atim.Nsec)
+	}
+
+	return info.ModTime()
+}
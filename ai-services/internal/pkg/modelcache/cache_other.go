@@ -0,0 +1,13 @@
+//go:build !linux
+
+package modelcache
+
+import (
+	"io/fs"
+	"time"
+)
+
+// accessTime returns the modification time of the file, the access time being read on Linux only.
+func accessTime(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
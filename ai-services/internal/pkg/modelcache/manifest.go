@@ -0,0 +1,309 @@
+package modelcache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+const (
+	// ManifestFileName is the manifest of a model, stored along with its files.
+	ManifestFileName    = ".ai-services-manifest.json"
+	manifestPermissions = 0o644
+	// hfCacheDir holds the state of hf download in the model directory: the metadata of the files downloaded,
+	// and the partial files the interrupted downloads resume from
+	hfCacheDir = ".cache"
+)
+
+var (
+	// ErrNoManifest is returned when the model has no manifest, as downloaded by an older release or copied by hand.
+	ErrNoManifest = errors.New("model has no manifest")
+
+	// sha256Regex matches the etag hf download records for the files stored with Git LFS, their SHA256.
+	sha256Regex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// Manifest lists the files of a model with their sizes and SHA256, as recorded once it was downloaded.
+type Manifest struct {
+	Model string `json:"model"`
+	// Revision is the revision the model was pinned to when downloaded, empty for the latest revision
+	Revision  string         `json:"revision,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Files     []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a file of the model, its path relative to the directory of the model.
+type ManifestFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	SHA256  string    `json:"sha256"`
+}
+
+// VerifyMode is how thoroughly the files of a model are checked against its manifest.
+type VerifyMode int
+
+const (
+	// VerifySize checks that the files exist with their sizes, which finds the truncated files.
+	VerifySize VerifyMode = iota
+	// VerifyChecksum also checks the SHA256 of the files, which reads them all.
+	VerifyChecksum
+)
+
+func manifestPath(dir, model string) string {
+	return filepath.Join(dir, model, ManifestFileName)
+}
+
+// RemoveManifest removes the manifest of the model, before the model is downloaded again.
+func RemoveManifest(dir, model string) error {
+	if err := os.Remove(manifestPath(dir, model)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the manifest of model %s: %w", model, err)
+	}
+
+	return nil
+}
+
+// ReadManifest returns the manifest of the model, ErrNoManifest if it has none.
+func ReadManifest(dir, model string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestPath(dir, model))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoManifest, model)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the manifest of model %s: %w", model, err)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of model %s: %w", model, err)
+	}
+
+	return manifest, nil
+}
+
+// WriteManifest records the manifest of the downloaded model. The SHA256 of the files stored with Git LFS are checked
+// against the ones hf download got from the hub, which finds the files corrupted during the download: they are removed
+// for the next download to fetch them again. The files left untouched since the previous manifest, if any, keep their
+// SHA256 instead of being read again.
+func WriteManifest(dir, model, revision string, previous *Manifest) (*Manifest, error) {
+	root := filepath.Join(dir, model)
+	manifest := &Manifest{Model: model, Revision: revision, CreatedAt: time.Now().UTC()}
+
+	err := walkModelFiles(root, func(rel string, info fs.FileInfo) error {
+		if file, ok := previous.unchanged(rel, info); ok {
+			manifest.Files = append(manifest.Files, file)
+
+			return nil
+		}
+
+		sum, err := fileSHA256(filepath.Join(root, rel))
+		if err != nil {
+			return err
+		}
+
+		if expected := hubSHA256(root, rel); expected != "" && expected != sum {
+			_ = os.Remove(filepath.Join(root, rel))
+			_ = os.Remove(hubMetadataPath(root, rel))
+
+			return fmt.Errorf("file %s of model %s is corrupted: SHA256 %s, expected %s", rel, model, sum, expected)
+		}
+
+		manifest.Files = append(manifest.Files, ManifestFile{Path: rel, Size: info.Size(), ModTime: info.ModTime().UTC(), SHA256: sum})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the manifest of model %s: %w", model, err)
+	}
+
+	if err := os.WriteFile(manifestPath(dir, model), data, manifestPermissions); err != nil {
+		return nil, fmt.Errorf("failed to write the manifest of model %s: %w", model, err)
+	}
+
+	return manifest, nil
+}
+
+// unchanged returns the file of the manifest if it has the size and the modification time of the file on disk.
+func (m *Manifest) unchanged(rel string, info fs.FileInfo) (ManifestFile, bool) {
+	if m == nil {
+		return ManifestFile{}, false
+	}
+
+	for _, file := range m.Files {
+		if file.Path == rel {
+			return file, file.Size == info.Size() && file.ModTime.Equal(info.ModTime())
+		}
+	}
+
+	return ManifestFile{}, false
+}
+
+// Verify checks the files of the model against its manifest, and returns the files missing, truncated or corrupted.
+func Verify(dir, model string, mode VerifyMode) error {
+	if _, err := os.Stat(filepath.Join(dir, model)); err != nil {
+		return fmt.Errorf("model %s is not present in %s", model, dir)
+	}
+
+	manifest, err := ReadManifest(dir, model)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Join(dir, model)
+	var errs []error
+	for _, file := range manifest.Files {
+		if err := verifyFile(filepath.Join(root, file.Path), file, mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("model %s is not intact, download it again: %w", model, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// CheckRevision checks that the model was downloaded at the revision it is pinned to, if any. The revision of the
+// models without a manifest is unknown, which does not match any.
+func CheckRevision(dir, model, revision string) error {
+	if revision == "" {
+		return nil
+	}
+
+	manifest, err := ReadManifest(dir, model)
+	if errors.Is(err, ErrNoManifest) {
+		return fmt.Errorf("model %s is pinned to revision %s, but the revision downloaded is unknown", model, revision)
+	}
+	if err != nil {
+		return err
+	}
+
+	if manifest.Revision != revision {
+		downloaded := manifest.Revision
+		if downloaded == "" {
+			downloaded = "latest"
+		}
+
+		return fmt.Errorf("model %s is pinned to revision %s, but revision %s is downloaded", model, revision, downloaded)
+	}
+
+	return nil
+}
+
+func verifyFile(path string, file ManifestFile, mode VerifyMode) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s: missing", file.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file.Path, err)
+	}
+
+	if info.Size() != file.Size {
+		return fmt.Errorf("%s: size %d, expected %d", file.Path, info.Size(), file.Size)
+	}
+
+	if mode != VerifyChecksum {
+		return nil
+	}
+
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file.Path, err)
+	}
+	if sum != file.SHA256 {
+		return fmt.Errorf("%s: SHA256 %s, expected %s", file.Path, sum, file.SHA256)
+	}
+
+	return nil
+}
+
+// walkModelFiles calls fn with the files of the model, skipping the state of hf download and the manifest.
+func walkModelFiles(root string, fn func(rel string, info fs.FileInfo) error) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && rel == hfCacheDir {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() || rel == ManifestFileName {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		return fn(rel, info)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the files of model %s: %w", root, err)
+	}
+
+	return nil
+}
+
+// hubSHA256 returns the SHA256 of the file as recorded by hf download, empty for the files not stored with Git LFS.
+// The metadata of a file holds its commit, its etag and its download time, a line each.
+func hubSHA256(root, rel string) string {
+	f, err := os.Open(hubMetadataPath(root, rel))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for line := 0; scanner.Scan(); line++ {
+		if line == 1 {
+			if etag := scanner.Text(); sha256Regex.MatchString(etag) {
+				return etag
+			}
+
+			break
+		}
+	}
+
+	return ""
+}
+
+func hubMetadataPath(root, rel string) string {
+	return filepath.Join(root, hfCacheDir, "huggingface", "download", rel+".metadata")
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,132 @@
+// Package modelcatalog holds the catalog of the models the application templates can serve: the roles each model
+// serves in the templates, whether it is supported on Spyre and on CPU, and the number of Spyre cards it needs.
+package modelcatalog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/assets"
+	"github.com/project-ai-services/ai-services/internal/pkg/modelsource"
+)
+
+const catalogPath = "models/catalog.yaml"
+
+// Accelerator is what a model is served on.
+type Accelerator string
+
+// The accelerators the models are served on.
+const (
+	AcceleratorSpyre Accelerator = "spyre"
+	AcceleratorCPU   Accelerator = "cpu"
+)
+
+// Entry is a model of the catalog.
+type Entry struct {
+	// Name is the name of the model on the Hugging Face hub (Eg:- ibm-granite/granite-3.3-8b-instruct)
+	Name string `yaml:"name"`
+	// Roles are the roles of the templates the model can serve (Eg:- instruct)
+	Roles []string `yaml:"roles"`
+	// Spyre holds the settings of the model on Spyre, nil if it is not supported on Spyre
+	Spyre *Spyre `yaml:"spyre,omitempty"`
+	// CPU tells whether the model is supported on CPU
+	CPU bool `yaml:"cpu"`
+}
+
+// Spyre holds the settings of a model served on Spyre.
+type Spyre struct {
+	// Cards is the number of Spyre cards the model is served on
+	Cards int `yaml:"cards"`
+}
+
+// Supports reports whether the model can be served on the accelerator.
+func (e *Entry) Supports(accelerator Accelerator) bool {
+	switch accelerator {
+	case AcceleratorSpyre:
+		return e.Spyre != nil && e.Spyre.Cards > 0
+	case AcceleratorCPU:
+		return e.CPU
+	default:
+		return false
+	}
+}
+
+// Catalog lists the models the application templates can serve.
+type Catalog struct {
+	Models []Entry `yaml:"models"`
+}
+
+// Model is a model of an application template, checked against the catalog.
+type Model struct {
+	// Spec is the model as set in the values, along with its revision or source if any
+	// (Eg:- ibm-granite/granite-3.3-8b-instruct@0a1b2c3)
+	Spec string
+	// Name is the name of the model, the directory it is downloaded to under the model directory
+	// and the name it is served with (Eg:- ibm-granite/granite-3.3-8b-instruct)
+	Name string
+	// SpyreCards is the number of Spyre cards the model is served on, 0 on CPU
+	SpyreCards int
+}
+
+// Load returns the catalog embedded in the CLI. The catalog is read once per run.
+var Load = sync.OnceValues(func() (*Catalog, error) {
+	data, err := assets.ModelFS.ReadFile(catalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the model catalog: %w", err)
+	}
+
+	catalog := &Catalog{}
+	if err := yaml.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse the model catalog: %w", err)
+	}
+
+	return catalog, nil
+})
+
+// Lookup returns the entry of the model, nil if the catalog does not list it.
+func (c *Catalog) Lookup(name string) *Entry {
+	for i := range c.Models {
+		if c.Models[i].Name == name {
+			return &c.Models[i]
+		}
+	}
+
+	return nil
+}
+
+// Supported returns the names of the models of the catalog which can serve the role on the accelerator.
+func (c *Catalog) Supported(role string, accelerator Accelerator) []string {
+	var names []string
+	for _, entry := range c.Models {
+		if slices.Contains(entry.Roles, role) && entry.Supports(accelerator) {
+			names = append(names, entry.Name)
+		}
+	}
+
+	return names
+}
+
+// Resolve checks that the model of the spec can serve the role on the accelerator, and returns it.
+func (c *Catalog) Resolve(role, spec string, accelerator Accelerator) (Model, error) {
+	m, err := modelsource.Parse(spec)
+	if err != nil {
+		return Model{}, err
+	}
+
+	entry := c.Lookup(m.Name)
+	if entry == nil || !slices.Contains(entry.Roles, role) || !entry.Supports(accelerator) {
+		return Model{}, fmt.Errorf("model %s is not supported as the %s model on %s, supported models: %s",
+			m.Name, role, accelerator, strings.Join(c.Supported(role, accelerator), ", "))
+	}
+
+	model := Model{Spec: spec, Name: m.Name}
+	if accelerator == AcceleratorSpyre {
+		model.SpyreCards = entry.Spyre.Cards
+	}
+
+	return model, nil
+}
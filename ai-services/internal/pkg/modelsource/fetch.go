@@ -0,0 +1,143 @@
+package modelsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+const (
+	filePermissions = 0o644
+	dirPermissions  = 0o755
+	// partialDir holds the files partially downloaded, under the directory of the model. It is hidden under the
+	// .cache directory hf download keeps its own state in, which the manifests of the models skip.
+	partialDir = ".cache/ai-services/download"
+)
+
+// remoteFile is a file of a model in a source, its path relative to the model.
+type remoteFile struct {
+	path string
+	size int64
+}
+
+// opener opens the file of the source from the offset.
+type opener func(ctx context.Context, file remoteFile, offset int64) (io.ReadCloser, error)
+
+// fetchFiles downloads the files to the directory of the model, skipping the files already downloaded with their
+// size, and resuming the files partially downloaded from where they stopped. The paths of the files come from the
+// source, any path leading outside of the directory of the model is refused.
+func fetchFiles(ctx context.Context, files []remoteFile, root string, open opener, progress func(current, total int64)) error {
+	for _, file := range files {
+		if !filepath.IsLocal(filepath.FromSlash(file.path)) {
+			return fmt.Errorf("invalid path '%s' of a file of the model", file.path)
+		}
+	}
+	total := totalSize(files)
+
+	var current atomic.Int64
+	report := func(n int64) { progress(current.Add(n), total) }
+
+	for _, file := range files {
+		if err := fetchFile(ctx, file, root, open, report); err != nil {
+			return fmt.Errorf("failed to download %s: %w", file.path, err)
+		}
+	}
+
+	return nil
+}
+
+func totalSize(files []remoteFile) int64 {
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+
+	return total
+}
+
+func fetchFile(ctx context.Context, file remoteFile, root string, open opener, report func(n int64)) error {
+	dst := filepath.Join(root, filepath.FromSlash(file.path))
+	if info, err := os.Stat(dst); err == nil && info.Size() == file.size {
+		report(file.size)
+
+		return nil
+	}
+
+	partial := filepath.Join(root, partialDir, filepath.FromSlash(file.path)+".incomplete")
+	if err := os.MkdirAll(filepath.Dir(partial), dirPermissions); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, filePermissions)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	// a partial file larger than the file is stale, the file changed in the source
+	if offset > file.size {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if offset, err = f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	report(offset)
+
+	if offset < file.size {
+		if err := copyFrom(ctx, f, file, offset, open, report); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), dirPermissions); err != nil {
+		return err
+	}
+
+	return os.Rename(partial, dst)
+}
+
+func copyFrom(ctx context.Context, f *os.File, file remoteFile, offset int64, open opener, report func(n int64)) error {
+	r, err := open(ctx, file, offset)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	n, err := io.Copy(f, &progressReader{r: r, report: report})
+	if err != nil {
+		return err
+	}
+
+	if offset+n != file.size {
+		return errors.New("the download was cut short, it resumes on the next attempt")
+	}
+
+	return nil
+}
+
+// progressReader reports the bytes read.
+type progressReader struct {
+	r      io.Reader
+	report func(n int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.report(int64(n))
+
+	return n, err
+}
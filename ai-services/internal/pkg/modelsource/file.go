@@ -0,0 +1,89 @@
+package modelsource
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileSource copies the models from a local directory, holding them as <root>/<model>.
+type fileSource struct {
+	root string
+}
+
+func (s *fileSource) Fetch(ctx context.Context, model, targetDir string, progress func(current, total int64)) error {
+	src := filepath.Join(s.root, model)
+
+	files, err := s.list(src)
+	if err != nil {
+		return fmt.Errorf("failed to read model %s from %s: %w", model, s.root, err)
+	}
+
+	open := func(_ context.Context, file remoteFile, offset int64) (io.ReadCloser, error) {
+		f, err := os.Open(filepath.Join(src, filepath.FromSlash(file.path)))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			_ = f.Close()
+
+			return nil, err
+		}
+
+		return f, nil
+	}
+
+	return fetchFiles(ctx, files, filepath.Join(targetDir, model), open, progress)
+}
+
+// Size returns the total size of the files of the model in the directory.
+func (s *fileSource) Size(_ context.Context, model string) (int64, error) {
+	files, err := s.list(filepath.Join(s.root, model))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read model %s from %s: %w", model, s.root, err)
+	}
+
+	return totalSize(files), nil
+}
+
+// list returns the regular files of the directory of the model, their paths relative to it.
+func (s *fileSource) list(src string) ([]remoteFile, error) {
+	var files []remoteFile
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		// the state of the tools which downloaded the model to the directory is not copied
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && rel != "." {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, remoteFile{path: filepath.ToSlash(rel), size: info.Size()})
+
+		return nil
+	})
+
+	return files, err
+}
+
+func (s *fileSource) String() string {
+	return SchemeFile + "://" + s.root
+}
@@ -0,0 +1,99 @@
+package modelsource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	huggingFaceURL = "https://huggingface.co"
+	// hfEndpointEnvName overrides the Hugging Face hub, as hf download expects
+	hfEndpointEnvName = "HF_ENDPOINT"
+	// hfOfflineEnvName is set on the hosts without access to the Hugging Face hub, as hf download expects
+	hfOfflineEnvName = "HF_HUB_OFFLINE"
+	hfTokenEnvName   = "HF_TOKEN"
+)
+
+// errHFOffline is returned by the lookups of the Hugging Face hub when HF_HUB_OFFLINE is set.
+var errHFOffline = errors.New(hfOfflineEnvName + " is set")
+
+// hfSource downloads the models with hf download from the Hugging Face hub, or from a compatible mirror.
+type hfSource struct {
+	endpoint string
+	revision string
+	download HFDownloader
+}
+
+func (s *hfSource) Fetch(ctx context.Context, model, targetDir string, progress func(current, total int64)) error {
+	return s.download(ctx, s.endpoint, model, s.revision, targetDir, progress)
+}
+
+// Size looks the model up with the API of the Hugging Face hub, or of the compatible mirror, at the revision of the
+// source if set. Nothing is looked up if HF_HUB_OFFLINE is set.
+func (s *hfSource) Size(ctx context.Context, model string) (int64, error) {
+	if offline, _ := strconv.ParseBool(os.Getenv(hfOfflineEnvName)); offline {
+		return 0, errHFOffline
+	}
+
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = huggingFaceURL
+		if e := os.Getenv(hfEndpointEnvName); e != "" {
+			endpoint = strings.TrimSuffix(e, "/")
+		}
+	}
+
+	apiPath := "/api/models/" + model
+	if s.revision != "" {
+		apiPath += "/revision/" + url.PathEscape(s.revision)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+apiPath+"?blobs=true", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build model info request: %w", err)
+	}
+	if token := os.Getenv(hfTokenEnvName); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch model info: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to fetch model info, status code: %d", resp.StatusCode)
+	}
+
+	var info struct {
+		Siblings []struct {
+			Size int64 `json:"size"`
+		} `json:"siblings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return 0, fmt.Errorf("failed to parse model info: %w", err)
+	}
+
+	var size int64
+	for _, file := range info.Siblings {
+		size += file.Size
+	}
+
+	return size, nil
+}
+
+func (s *hfSource) String() string {
+	if s.endpoint == "" {
+		return "Hugging Face"
+	}
+
+	return s.endpoint
+}
@@ -0,0 +1,279 @@
+package modelsource
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service         = "s3"
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3DateFormat      = "20060102"
+	s3TimeFormat      = "20060102T150405Z"
+	// s3ErrorBodyLimit bounds the body of a failed response logged, enough for the error code and message
+	s3ErrorBodyLimit = 1024
+)
+
+// s3Source downloads the models from a bucket of an S3-compatible storage, holding them as <prefix>/<model>.
+// The requests are signed with AWS Signature Version 4, which MinIO and the other S3-compatible storages support.
+type s3Source struct {
+	cfg    S3
+	bucket string
+	prefix string
+	client *http.Client
+}
+
+func newS3Source(cfg S3, bucket, prefix string) *s3Source {
+	return &s3Source{cfg: cfg, bucket: bucket, prefix: prefix, client: http.DefaultClient}
+}
+
+// listBucketResult is the response of ListObjectsV2.
+type listBucketResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3Source) Fetch(ctx context.Context, model, targetDir string, progress func(current, total int64)) error {
+	keyPrefix := strings.TrimPrefix(s.prefix+"/"+model+"/", "/")
+
+	files, err := s.list(ctx, keyPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list model %s in %s: %w", model, s, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("model %s not found in %s", model, s)
+	}
+
+	open := func(ctx context.Context, file remoteFile, offset int64) (io.ReadCloser, error) {
+		header := http.Header{}
+		if offset > 0 {
+			header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		}
+
+		resp, err := s.do(ctx, "/"+keyPrefix+file.path, nil, header)
+		if err != nil {
+			return nil, err
+		}
+
+		// a storage ignoring the range sends the whole file, which cannot be appended to the partial one
+		if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+			_ = resp.Body.Close()
+
+			return nil, fmt.Errorf("the storage does not support resuming the download, status code: %d", resp.StatusCode)
+		}
+
+		return resp.Body, nil
+	}
+
+	return fetchFiles(ctx, files, filepath.Join(targetDir, model), open, progress)
+}
+
+// Size returns the total size of the objects of the model in the bucket.
+func (s *s3Source) Size(ctx context.Context, model string) (int64, error) {
+	files, err := s.list(ctx, strings.TrimPrefix(s.prefix+"/"+model+"/", "/"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list model %s in %s: %w", model, s, err)
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("model %s not found in %s", model, s)
+	}
+
+	return totalSize(files), nil
+}
+
+// list returns the objects under the key prefix, their paths relative to it.
+func (s *s3Source) list(ctx context.Context, keyPrefix string) ([]remoteFile, error) {
+	var files []remoteFile
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {keyPrefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, "/", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the object list: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			// the markers of the directories created by some clients hold no data
+			if strings.HasSuffix(object.Key, "/") {
+				continue
+			}
+			files = append(files, remoteFile{path: strings.TrimPrefix(object.Key, keyPrefix), size: object.Size})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return files, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends the signed GET request for the path of the bucket, and returns the response if it succeeded.
+func (s *s3Source) do(ctx context.Context, path string, query url.Values, header http.Header) (*http.Response, error) {
+	u, err := s.url(path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if s.cfg.AccessKeyID != "" {
+		s.sign(req, time.Now().UTC())
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("request to %s failed, status code: %d: %s", u.Redacted(), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
+
+// url returns the URL of the path of the bucket: path-style on a custom endpoint, as MinIO expects by default,
+// virtual-hosted-style on AWS S3.
+func (s *s3Source) url(path string, query url.Values) (*url.URL, error) {
+	var u *url.URL
+	if s.cfg.Endpoint != "" {
+		endpoint, err := url.Parse(strings.TrimSuffix(s.cfg.Endpoint, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3 endpoint %s: %w", s.cfg.Endpoint, err)
+		}
+		u = endpoint.JoinPath(s.bucket, path)
+	} else {
+		u = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.cfg.Region), Path: path}
+	}
+
+	// JoinPath drops the trailing slash of the bucket root the objects are listed from
+	if strings.HasSuffix(path, "/") && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	// the path is sent encoded as it is signed
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = canonicalQuery(query)
+
+	return u, nil
+}
+
+// sign signs the request with AWS Signature Version 4, the payload left unsigned unless its hash is already set in
+// the X-Amz-Content-Sha256 header.
+func (s *s3Source) sign(req *http.Request, now time.Time) {
+	date := now.Format(s3DateFormat)
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = s3UnsignedPayload
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           req.Header.Get("X-Amz-Date"),
+	}
+	if r := req.Header.Get("Range"); r != "" {
+		headers = append(headers, "range")
+		values["range"] = r
+	}
+	slices.Sort(headers)
+
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + values[h] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.cfg.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{s3Algorithm, req.Header.Get("X-Amz-Date"), scope, sha256Hex(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	for _, part := range []string{s.cfg.Region, s3Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func (s *s3Source) String() string {
+	return strings.TrimSuffix(SchemeS3+"://"+s.bucket+"/"+s.prefix, "/")
+}
+
+// canonicalQuery encodes the query sorted by key, with the spaces as %20, as the signature expects.
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// escapePath encodes each segment of the path, every byte but the unreserved characters, as the signature expects.
+func escapePath(path string) string {
+	var b strings.Builder
+	for _, c := range []byte(path) {
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
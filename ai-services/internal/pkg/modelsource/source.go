@@ -0,0 +1,253 @@
+// Package modelsource resolves where the models of the application templates are downloaded from: the Hugging Face
+// hub, a Hugging Face compatible mirror, a local directory or an S3-compatible storage, chosen by the URI scheme of the
+// model annotation or by the mirrors of the model sources config.
+package modelsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+// The schemes of the model sources.
+const (
+	SchemeHF    = "hf"
+	SchemeHTTPS = "https"
+	SchemeHTTP  = "http"
+	SchemeFile  = "file"
+	SchemeS3    = "s3"
+)
+
+// modelNameSegments is the number of trailing path segments of a URI naming the model (Eg:- ibm-granite/granite-3.3-8b-instruct).
+const modelNameSegments = 2
+
+// Source downloads the files of a model.
+type Source interface {
+	// Fetch downloads the files of the model to <targetDir>/<model>, resuming the files partially downloaded.
+	Fetch(ctx context.Context, model, targetDir string, progress func(current, total int64)) error
+	// Size returns the total size of the files of the model in the source.
+	Size(ctx context.Context, model string) (int64, error)
+	// String describes the source in the logs.
+	String() string
+}
+
+// Model is a model of an application template, as set in its model annotation: either the name of the model on the
+// Hugging Face hub (Eg:- ibm-granite/granite-3.3-8b-instruct), or a URI whose last two path segments name the model
+// (Eg:- s3://models/approved/ibm-granite/granite-3.3-8b-instruct). Either may be pinned to a revision of the model
+// with an @<revision> suffix (Eg:- ibm-granite/granite-3.3-8b-instruct@0a1b2c3).
+type Model struct {
+	// Name is the name of the model, the directory it is downloaded to under the model directory
+	Name string
+	// Revision is the branch, tag or commit the model is pinned to, empty for the latest revision
+	Revision string
+	// Location is the URI of the source the model is fetched from, the model name excluded. Empty for the models
+	// without a scheme, fetched from the mirrors of the config or from the Hugging Face hub.
+	Location *url.URL
+}
+
+// Parse parses the model annotation.
+func Parse(spec string) (Model, error) {
+	rest, revision, pinned := cutRevision(spec)
+	if pinned && revision == "" {
+		return Model{}, fmt.Errorf("invalid model %s: the revision after @ is empty", spec)
+	}
+
+	if !strings.Contains(rest, "://") {
+		return Model{Name: rest, Revision: revision}, nil
+	}
+
+	u, err := url.Parse(rest)
+	if err != nil {
+		return Model{}, fmt.Errorf("invalid model %s: %w", spec, err)
+	}
+
+	// the host of hf:// URIs is the organization of the model, the host of s3:// URIs is the bucket
+	path := strings.Trim(u.Path, "/")
+	if u.Scheme == SchemeHF {
+		path = strings.Trim(u.Host+"/"+path, "/")
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) < modelNameSegments || slices.Contains(segments, "") {
+		return Model{}, fmt.Errorf("invalid model %s: the URI must end with <organization>/<name>", spec)
+	}
+
+	name := strings.Join(segments[len(segments)-modelNameSegments:], "/")
+	location := *u
+	location.Path = "/" + strings.Join(segments[:len(segments)-modelNameSegments], "/")
+	if u.Scheme == SchemeHF {
+		location.Host, location.Path = "", ""
+	}
+
+	return Model{Name: name, Revision: revision, Location: &location}, nil
+}
+
+// cutRevision splits the revision off the model annotation, the @ of the user info of a URI left untouched.
+func cutRevision(spec string) (string, string, bool) {
+	i := strings.LastIndex(spec, "@")
+	if i == -1 || strings.Contains(spec[i:], "/") {
+		return spec, "", false
+	}
+
+	return spec[:i], spec[i+1:], true
+}
+
+// Name returns the name of the model of the annotation, the annotation itself if it cannot be parsed.
+func Name(spec string) string {
+	m, err := Parse(spec)
+	if err != nil {
+		return spec
+	}
+
+	return m.Name
+}
+
+// Mirror fetches the models matching its prefix from its location.
+type Mirror struct {
+	// Prefix is the organization, optionally followed by the name, of the models fetched from the mirror
+	// (Eg:- ibm-granite). Empty matches all the models.
+	Prefix string `yaml:"prefix"`
+	// Location is the URI the models are fetched from, followed by their names (Eg:- s3://models/approved,
+	// file:///srv/models or https://hf-mirror.example.com for a Hugging Face compatible mirror)
+	Location string `yaml:"location"`
+}
+
+// S3 holds the settings of the S3-compatible storage of the s3:// sources.
+type S3 struct {
+	// Endpoint is the URL of the S3-compatible storage (Eg:- http://minio.example.com:9000), AWS S3 when unset
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+	// AccessKeyID and SecretAccessKey sign the requests, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY when unset,
+	// the requests are anonymous if neither is set
+	AccessKeyID     string `yaml:"accessKeyID"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+}
+
+// Config is the model sources configuration of the CLI.
+type Config struct {
+	Mirrors []Mirror `yaml:"mirrors"`
+	S3      S3       `yaml:"s3"`
+}
+
+const defaultS3Region = "us-east-1"
+
+// Load returns the model sources configuration read from vars.ModelSourcesConfig, empty if the file does not exist.
+// The configuration is read once per run.
+var Load = sync.OnceValues(func() (*Config, error) {
+	return read(vars.ModelSourcesConfig)
+})
+
+func read(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg.withDefaults(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model sources config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse model sources config %s: %w", path, err)
+	}
+
+	for i, m := range cfg.Mirrors {
+		if m.Location == "" {
+			return nil, fmt.Errorf("invalid model sources config %s: mirror %d requires a location", path, i)
+		}
+		cfg.Mirrors[i].Prefix = strings.Trim(m.Prefix, "/")
+		cfg.Mirrors[i].Location = strings.TrimSuffix(m.Location, "/")
+	}
+
+	return cfg.withDefaults(), nil
+}
+
+func (c *Config) withDefaults() *Config {
+	if c.S3.Region == "" {
+		c.S3.Region = defaultS3Region
+	}
+	if c.S3.AccessKeyID == "" {
+		c.S3.AccessKeyID, c.S3.SecretAccessKey = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+
+	return c
+}
+
+// Location returns the URI the model is fetched from: the URI of its annotation, else the location of the mirror with
+// the longest matching prefix, else the Hugging Face hub.
+func (c *Config) Location(m Model) (*url.URL, error) {
+	if m.Location != nil {
+		return m.Location, nil
+	}
+
+	match := -1
+	for i, mirror := range c.Mirrors {
+		rest, ok := strings.CutPrefix(m.Name, mirror.Prefix)
+		// a prefix only matches whole path segments, ibm does not match ibm-granite/granite-3.3-8b-instruct
+		if !ok || (mirror.Prefix != "" && rest != "" && rest[0] != '/') {
+			continue
+		}
+		if match == -1 || len(mirror.Prefix) > len(c.Mirrors[match].Prefix) {
+			match = i
+		}
+	}
+
+	if match == -1 {
+		return &url.URL{Scheme: SchemeHF}, nil
+	}
+
+	u, err := url.Parse(c.Mirrors[match].Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location of model mirror %s: %w", c.Mirrors[match].Location, err)
+	}
+
+	return u, nil
+}
+
+// HFDownloader downloads the revision of the model, the latest when empty, with hf download from the Hugging Face
+// compatible endpoint, the Hugging Face hub when empty.
+type HFDownloader func(ctx context.Context, endpoint, model, revision, targetDir string, progress func(current, total int64)) error
+
+// Resolve returns the model of the annotation and the source it is fetched from, the models of the Hugging Face hub
+// and of its mirrors being downloaded with hf.
+func (c *Config) Resolve(spec string, hf HFDownloader) (Model, Source, error) {
+	m, err := Parse(spec)
+	if err != nil {
+		return Model{}, nil, err
+	}
+
+	location, err := c.Location(m)
+	if err != nil {
+		return Model{}, nil, err
+	}
+
+	// the directories and the buckets hold a single revision of the model, which cannot be told apart from another
+	if m.Revision != "" && (location.Scheme == SchemeFile || location.Scheme == SchemeS3) {
+		return Model{}, nil, fmt.Errorf("model %s is pinned to revision %s, which cannot be fetched from %s: "+
+			"the pinned models are downloaded from the Hugging Face hub or a Hugging Face compatible mirror", m.Name, m.Revision, location.Redacted())
+	}
+
+	switch location.Scheme {
+	case SchemeHF:
+		return m, &hfSource{revision: m.Revision, download: hf}, nil
+	case SchemeHTTPS, SchemeHTTP:
+		return m, &hfSource{endpoint: strings.TrimSuffix(location.String(), "/"), revision: m.Revision, download: hf}, nil
+	case SchemeFile:
+		return m, &fileSource{root: location.Path}, nil
+	case SchemeS3:
+		return m, newS3Source(c.S3, location.Host, strings.Trim(location.Path, "/")), nil
+	default:
+		return Model{}, nil, fmt.Errorf("unsupported source %s of model %s, supported schemes: %s, %s, %s, %s",
+			location.Scheme, spec, SchemeHF, SchemeHTTPS, SchemeFile, SchemeS3)
+	}
+}
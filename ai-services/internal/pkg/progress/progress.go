@@ -0,0 +1,262 @@
+// Package progress reports the progress of the downloads run in parallel (Eg:- image pulls, model downloads):
+// the bytes downloaded by each of them and the ETA of all of them. On a terminal the progress is redrawn in place,
+// otherwise it is printed as plain lines, suited to CI logs.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	redrawInterval = 500 * time.Millisecond
+	// plainInterval is the interval of the progress lines printed when the output is not a terminal
+	plainInterval = 15 * time.Second
+	barWidth      = 20
+	nameWidth     = 48
+	unitBase      = 1000
+)
+
+type state int
+
+const (
+	statePending state = iota
+	stateRunning
+	stateDone
+	stateFailed
+)
+
+// Tracker tracks the progress of a set of items.
+type Tracker struct {
+	title string
+	out   io.Writer
+	tty   bool
+
+	mu      sync.Mutex
+	items   []*Item
+	start   time.Time
+	drawn   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// Item is a download tracked by a Tracker.
+type Item struct {
+	tracker *Tracker
+	name    string
+	state   state
+	current int64
+	// total is 0 while the size is unknown
+	total int64
+}
+
+// New returns a tracker writing to stderr, the progress redrawn in place if stderr is a terminal.
+func New(title string) *Tracker {
+	return &Tracker{
+		title: title,
+		out:   os.Stderr,
+		tty:   term.IsTerminal(int(os.Stderr.Fd())),
+	}
+}
+
+// Add adds an item to the tracker, pending until it is updated.
+func (t *Tracker) Add(name string) *Item {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	item := &Item{tracker: t, name: name}
+	t.items = append(t.items, item)
+
+	return item
+}
+
+// Start prints the progress until Stop is called.
+func (t *Tracker) Start() {
+	t.start = time.Now()
+	t.stop = make(chan struct{})
+	t.stopped = make(chan struct{})
+
+	fmt.Fprintln(t.out, t.title)
+
+	interval := plainInterval
+	if t.tty {
+		interval = redrawInterval
+	}
+
+	go func() {
+		defer close(t.stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.print()
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops printing the progress, after printing it a last time.
+func (t *Tracker) Stop() {
+	close(t.stop)
+	<-t.stopped
+
+	if t.tty {
+		t.print()
+	} else {
+		t.mu.Lock()
+		fmt.Fprintln(t.out, t.summary())
+		t.mu.Unlock()
+	}
+}
+
+// Update sets the bytes downloaded so far and the total size of the item, total is 0 if unknown.
+func (i *Item) Update(current, total int64) {
+	i.tracker.mu.Lock()
+	defer i.tracker.mu.Unlock()
+
+	i.state = stateRunning
+	i.current, i.total = current, total
+}
+
+// Done marks the item as downloaded.
+func (i *Item) Done() {
+	i.finish(stateDone, "done")
+}
+
+// Fail marks the item as failed.
+func (i *Item) Fail() {
+	i.finish(stateFailed, "failed")
+}
+
+func (i *Item) finish(s state, label string) {
+	i.tracker.mu.Lock()
+	defer i.tracker.mu.Unlock()
+
+	i.state = s
+	if s == stateDone && i.total > 0 {
+		i.current = i.total
+	}
+
+	// on a terminal the items are redrawn in place, otherwise their completion is printed once
+	if !i.tracker.tty {
+		fmt.Fprintf(i.tracker.out, "%s: %s (%s)\n", i.name, label, FormatBytes(i.current))
+	}
+}
+
+func (t *Tracker) print() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.tty {
+		fmt.Fprintln(t.out, t.summary())
+
+		return
+	}
+
+	var b strings.Builder
+	// move the cursor back to the first line drawn, and redraw all the lines
+	if t.drawn > 0 {
+		fmt.Fprintf(&b, "\033[%dA", t.drawn)
+	}
+	for _, item := range t.items {
+		fmt.Fprintf(&b, "\033[2K%s\n", item.line())
+	}
+	fmt.Fprintf(&b, "\033[2K%s\n", t.summary())
+
+	t.drawn = len(t.items) + 1
+	fmt.Fprint(t.out, b.String())
+}
+
+func (i *Item) line() string {
+	name := i.name
+	if len(name) > nameWidth {
+		name = "..." + name[len(name)-nameWidth+len("..."):]
+	}
+
+	status := ""
+	switch i.state {
+	case statePending:
+		status = "waiting"
+	case stateRunning:
+		status = FormatBytes(i.current)
+		if i.total > 0 {
+			status += " / " + FormatBytes(i.total)
+		}
+	case stateDone:
+		status = "done"
+	case stateFailed:
+		status = "failed"
+	}
+
+	return fmt.Sprintf("  %-*s %s %s", nameWidth, name, i.bar(), status)
+}
+
+func (i *Item) bar() string {
+	filled := 0
+	switch {
+	case i.state == stateDone:
+		filled = barWidth
+	case i.total > 0:
+		filled = int(min(i.current*barWidth/i.total, barWidth))
+	}
+
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "]"
+}
+
+// summary returns the aggregate progress of the items, the ETA computed from the average rate since the start,
+// over the items whose size is known.
+func (t *Tracker) summary() string {
+	var current, total int64
+	done := 0
+	for _, item := range t.items {
+		current += item.current
+		total += item.total
+		if item.state == stateDone || item.state == stateFailed {
+			done++
+		}
+	}
+
+	elapsed := time.Since(t.start)
+	line := fmt.Sprintf("%d/%d done, %s", done, len(t.items), FormatBytes(current))
+	if total > 0 {
+		line += " / " + FormatBytes(total)
+	}
+
+	rate := float64(current) / elapsed.Seconds()
+	if rate > 0 {
+		line += fmt.Sprintf(" at %s/s", FormatBytes(int64(rate)))
+	}
+	if rate > 0 && total > current && done < len(t.items) {
+		eta := time.Duration(float64(total-current) / rate * float64(time.Second))
+		line += ", ETA " + eta.Round(time.Second).String()
+	}
+
+	return line + ", elapsed " + elapsed.Round(time.Second).String()
+}
+
+// FormatBytes formats the size in decimal units, the units of the registries and the Hugging Face hub.
+func FormatBytes(n int64) string {
+	if n < unitBase {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	value := float64(n)
+	unit := -1
+	for value >= unitBase && unit < len("kMGTP")-1 {
+		value /= unitBase
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %cB", value, "kMGTP"[unit])
+}
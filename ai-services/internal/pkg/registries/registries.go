@@ -0,0 +1,176 @@
+// Package registries reads the registry configuration of the CLI: the mirrors the image references of the
+// application templates are rewritten to, so that the applications are deployed from a private registry
+// without editing the templates, and the credentials and TLS settings the images are pulled with.
+package registries
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+// Mirror rewrites the image references starting with its prefix, like the mirrors of registries.conf.
+type Mirror struct {
+	// Prefix is the registry, optionally followed by a repository namespace, of the references rewritten (Eg:- icr.io/ppc64le-oss)
+	Prefix string `yaml:"prefix"`
+	// Location replaces the prefix in the rewritten references (Eg:- quay.example.com/ppc64le-oss)
+	Location string `yaml:"location"`
+}
+
+// Registry holds the pull settings of the registries, or the repositories, matching its prefix.
+type Registry struct {
+	// Prefix is the registry, optionally followed by a repository namespace, the settings apply to (Eg:- registry.redhat.io)
+	Prefix string `yaml:"prefix"`
+	// Username and Password authenticate against the registry, instead of the auth file
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// TLSVerify set to false skips the verification of the certificate of the registry
+	TLSVerify *bool `yaml:"tlsVerify"`
+}
+
+// Config is the registry configuration of the CLI.
+type Config struct {
+	Mirrors []Mirror `yaml:"mirrors"`
+	// AuthFile is the auth file the images are pulled with, REGISTRY_AUTH_FILE when unset
+	AuthFile string `yaml:"authFile"`
+	// Retries is the number of times the runtime retries a failed pull, its default when unset
+	Retries    *uint      `yaml:"retries"`
+	Registries []Registry `yaml:"registries"`
+}
+
+// authFileEnv is the environment variable podman and skopeo read the auth file from.
+const authFileEnv = "REGISTRY_AUTH_FILE"
+
+// imageFieldRegex matches the image fields of a rendered manifest, the reference in the third group.
+var imageFieldRegex = regexp.MustCompile(`(?m)^(\s*(?:-\s+)?image:\s*)(["']?)([^"'\s#]+)(["']?)`)
+
+// Load returns the registry configuration read from vars.RegistriesConfig, empty if the file does not exist.
+// The configuration is read once per run.
+var Load = sync.OnceValues(func() (*Config, error) {
+	return read(vars.RegistriesConfig)
+})
+
+func read(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registries config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse registries config %s: %w", path, err)
+	}
+
+	for i, m := range cfg.Mirrors {
+		if m.Prefix == "" || m.Location == "" {
+			return nil, fmt.Errorf("invalid registries config %s: mirror %d requires both a prefix and a location", path, i)
+		}
+		cfg.Mirrors[i].Prefix = strings.TrimSuffix(m.Prefix, "/")
+		cfg.Mirrors[i].Location = strings.TrimSuffix(m.Location, "/")
+	}
+
+	for i, r := range cfg.Registries {
+		if r.Prefix == "" {
+			return nil, fmt.Errorf("invalid registries config %s: registry %d requires a prefix", path, i)
+		}
+		cfg.Registries[i].Prefix = strings.TrimSuffix(r.Prefix, "/")
+	}
+
+	if cfg.AuthFile == "" {
+		cfg.AuthFile = os.Getenv(authFileEnv)
+	}
+
+	return cfg, nil
+}
+
+// Rewrite returns the reference rewritten by the mirror with the longest matching prefix, or the reference itself
+// if none matches.
+func (c *Config) Rewrite(ref string) string {
+	match := longestPrefix(ref, len(c.Mirrors), func(i int) string { return c.Mirrors[i].Prefix })
+	if match == -1 {
+		return ref
+	}
+
+	return c.Mirrors[match].Location + strings.TrimPrefix(ref, c.Mirrors[match].Prefix)
+}
+
+// PullOptions returns the options the reference is pulled with, the settings of the registry with the longest
+// matching prefix along with the auth file and the retries.
+func (c *Config) PullOptions(ref string) *types.PullOptions {
+	opts := &types.PullOptions{AuthFile: c.AuthFile, Retries: c.Retries}
+
+	match := longestPrefix(ref, len(c.Registries), func(i int) string { return c.Registries[i].Prefix })
+	if match != -1 {
+		r := c.Registries[match]
+		opts.Username, opts.Password, opts.TLSVerify = r.Username, r.Password, r.TLSVerify
+	}
+
+	return opts
+}
+
+// longestPrefix returns the index of the longest of the n prefixes matching the reference, -1 if none matches.
+// A prefix only matches whole path components, icr.io/ai does not match icr.io/ai-services/tools.
+func longestPrefix(ref string, n int, prefix func(i int) string) int {
+	match := -1
+	for i := range n {
+		rest, ok := strings.CutPrefix(ref, prefix(i))
+		if !ok || (rest != "" && !strings.ContainsAny(rest[:1], "/:@")) {
+			continue
+		}
+		if match == -1 || len(prefix(i)) > len(prefix(match)) {
+			match = i
+		}
+	}
+
+	return match
+}
+
+// RewriteManifest rewrites the references of the image fields of the rendered manifests.
+func (c *Config) RewriteManifest(manifest []byte) []byte {
+	if len(c.Mirrors) == 0 {
+		return manifest
+	}
+
+	return RewriteImageFields(manifest, c.Rewrite)
+}
+
+// RewriteImageFields replaces the references of the image fields of the rendered manifests with the ones returned by rewrite.
+func RewriteImageFields(manifest []byte, rewrite func(ref string) string) []byte {
+	return imageFieldRegex.ReplaceAllFunc(manifest, func(field []byte) []byte {
+		groups := imageFieldRegex.FindSubmatch(field)
+
+		return []byte(string(groups[1]) + string(groups[2]) + rewrite(string(groups[3])) + string(groups[4]))
+	})
+}
+
+// RewriteImage rewrites the reference with the mirrors of the registry configuration.
+func RewriteImage(ref string) (string, error) {
+	cfg, err := Load()
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Rewrite(ref), nil
+}
+
+// PullOptions returns the options the reference is pulled with, from the registry configuration.
+func PullOptions(ref string) (*types.PullOptions, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.PullOptions(ref), nil
+}
@@ -0,0 +1,59 @@
+package registries
+
+import "testing"
+
+func TestLongestPrefix(t *testing.T) {
+	prefixes := []string{"icr.io", "icr.io/ai", "icr.io/ai-services", "quay.io/org"}
+
+	tests := []struct {
+		name string
+		ref  string
+		want int
+	}{
+		{name: "registry only", ref: "icr.io/other/image:1.0", want: 0},
+		{name: "longest namespace", ref: "icr.io/ai-services/tools:latest", want: 2},
+		{name: "whole path component", ref: "icr.io/ai/vllm:0.9", want: 1},
+		{name: "exact match", ref: "quay.io/org", want: 3},
+		{name: "tag after prefix", ref: "quay.io/org:1.0", want: 3},
+		{name: "digest after prefix", ref: "quay.io/org@sha256:abc", want: 3},
+		{name: "partial component", ref: "quay.io/organization/image", want: -1},
+		{name: "no match", ref: "docker.io/library/busybox", want: -1},
+		{name: "registry partial component", ref: "icr.iox/image", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := longestPrefix(tt.ref, len(prefixes), func(i int) string { return prefixes[i] })
+			if got != tt.want {
+				t.Errorf("longestPrefix(%q) = %d, want %d", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	cfg := &Config{Mirrors: []Mirror{
+		{Prefix: "icr.io", Location: "mirror.example.com/icr"},
+		{Prefix: "icr.io/ppc64le-oss", Location: "quay.example.com/oss"},
+	}}
+
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{name: "registry mirror", ref: "icr.io/ai-services/tools:1.0", want: "mirror.example.com/icr/ai-services/tools:1.0"},
+		{name: "namespace mirror", ref: "icr.io/ppc64le-oss/vllm:0.9", want: "quay.example.com/oss/vllm:0.9"},
+		{name: "digest", ref: "icr.io/ppc64le-oss/vllm@sha256:abc", want: "quay.example.com/oss/vllm@sha256:abc"},
+		{name: "partial component", ref: "icr.io/ppc64le-oss-extra/vllm:0.9", want: "mirror.example.com/icr/ppc64le-oss-extra/vllm:0.9"},
+		{name: "no mirror", ref: "docker.io/library/busybox:latest", want: "docker.io/library/busybox:latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.Rewrite(tt.ref); got != tt.want {
+				t.Errorf("Rewrite(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
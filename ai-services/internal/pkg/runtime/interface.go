@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"context"
 	"io"
 
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
@@ -9,7 +10,7 @@ import (
 type Runtime interface {
 	// Image operations
 	ListImages() ([]types.Image, error)
-	PullImage(image string) error
+	PullImage(image string, opts *types.PullOptions) error
 
 	// Pod operations
 	ListPods(filters map[string][]string) ([]types.Pod, error)
@@ -26,12 +27,24 @@ type Runtime interface {
 	InspectContainer(nameOrId string) (*types.Container, error)
 	ContainerExists(nameOrID string) (bool, error)
 	ContainerLogs(containerNameOrID string) error
+	// ExecContainer runs the command inside the container and returns its exit code
+	ExecContainer(nameOrID string, cmd []string) (int, error)
+
+	// Event operations
+	// Events streams the runtime events matching the filters, until the context is cancelled
+	Events(ctx context.Context, filters map[string][]string) (<-chan types.Event, error)
 
 	// Network operations
 	ListRoutes() ([]types.Route, error)
 
 	// PVC operations
 	DeletePVCs(appLabel string) error
+	// EnsurePVC creates the persistent volume claim, unless it exists
+	EnsurePVC(pvc types.PVC) error
+
+	// Job operations
+	// RunJob runs the job to completion, replacing a previous run of it, and streams the logs of its pod to the output
+	RunJob(ctx context.Context, job types.Job, output io.Writer) error
 
 	// Runtime type identification
 	Type() types.RuntimeType
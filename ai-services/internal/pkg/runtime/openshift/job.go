@@ -0,0 +1,278 @@
+package openshift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	jobPollInterval = 2 * time.Second
+	// jobUnschedulableTimeout is how long the pod of a Job may stay unschedulable, Eg:- on an unbound PVC, before the
+	// Job is failed
+	jobUnschedulableTimeout = 2 * time.Minute
+	// jobNameLabel is set by the Job controller on the pods of a Job
+	jobNameLabel = "batch.kubernetes.io/job-name"
+)
+
+// jobPodFailureReasons are the reasons a container of the pod of a Job waits for, which it never recovers from.
+var jobPodFailureReasons = []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError"}
+
+// EnsurePVC creates the persistent volume claim in the namespace of the client, along with the namespace,
+// unless it exists. The claim is ReadWriteMany, for the pods of several nodes to mount it.
+func (kc *OpenshiftClient) EnsurePVC(pvc types.PVC) error {
+	if err := kc.ensureNamespace(); err != nil {
+		return err
+	}
+
+	_, err := kc.KubeClient.CoreV1().PersistentVolumeClaims(kc.Namespace).Get(kc.Ctx, pvc.Name, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get PVC '%s': %w", pvc.Name, err)
+	}
+
+	size, err := resource.ParseQuantity(pvc.Size)
+	if err != nil {
+		return fmt.Errorf("invalid size '%s' of PVC '%s': %w", pvc.Size, pvc.Name, err)
+	}
+
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: pvc.Name, Labels: pvc.Labels},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if pvc.StorageClass != "" {
+		claim.Spec.StorageClassName = &pvc.StorageClass
+	}
+
+	if _, err := kc.KubeClient.CoreV1().PersistentVolumeClaims(kc.Namespace).Create(kc.Ctx, claim, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create PVC '%s': %w", pvc.Name, err)
+	}
+	logger.Infof("Created PVC '%s'\n", pvc.Name, logger.VerbosityLevelDebug)
+
+	return nil
+}
+
+// ensureNamespace creates the namespace of the client unless it exists, as Helm does on install.
+func (kc *OpenshiftClient) ensureNamespace() error {
+	_, err := kc.KubeClient.CoreV1().Namespaces().Get(kc.Ctx, kc.Namespace, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get namespace '%s': %w", kc.Namespace, err)
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: kc.Namespace}}
+	if _, err := kc.KubeClient.CoreV1().Namespaces().Create(kc.Ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace '%s': %w", kc.Namespace, err)
+	}
+
+	return nil
+}
+
+// RunJob runs the job in the namespace of the client and waits for it to complete, the logs of its pod streamed to
+// the output. The job is not retried by Kubernetes, a failed job is reported as is for the caller to run it again.
+// The wait is bounded by the deadline of the context, past which the job is deleted.
+func (kc *OpenshiftClient) RunJob(ctx context.Context, job types.Job, output io.Writer) error {
+	err := kc.runJob(ctx, job, output)
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+
+	// the job would keep running otherwise, as only the wait for it stopped
+	propagation := metav1.DeletePropagationBackground
+	delErr := kc.KubeClient.BatchV1().Jobs(kc.Namespace).Delete(context.WithoutCancel(ctx), job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if delErr != nil && !apierrors.IsNotFound(delErr) {
+		logger.Warningf("failed to delete job '%s': %v\n", job.Name, delErr)
+	}
+
+	return fmt.Errorf("job '%s' did not complete: %w", job.Name, err)
+}
+
+func (kc *OpenshiftClient) runJob(ctx context.Context, job types.Job, output io.Writer) error {
+	if err := kc.deleteJob(ctx, job.Name); err != nil {
+		return err
+	}
+
+	if _, err := kc.KubeClient.BatchV1().Jobs(kc.Namespace).Create(ctx, toJob(job), metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create job '%s': %w", job.Name, err)
+	}
+
+	podName, err := kc.waitForJobPod(ctx, job.Name)
+	if err != nil {
+		return err
+	}
+
+	stream, err := kc.KubeClient.CoreV1().Pods(kc.Namespace).GetLogs(podName, &corev1.PodLogOptions{Follow: true}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stream the logs of job '%s': %w", job.Name, err)
+	}
+	_, err = io.Copy(output, stream)
+	_ = stream.Close()
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("failed to stream the logs of job '%s': %w", job.Name, err)
+	}
+
+	return kc.waitForJob(ctx, job.Name)
+}
+
+// deleteJob deletes the previous run of the job along with its pods, and waits for it to be gone.
+func (kc *OpenshiftClient) deleteJob(ctx context.Context, name string) error {
+	propagation := metav1.DeletePropagationForeground
+	err := kc.KubeClient.BatchV1().Jobs(kc.Namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete the previous run of job '%s': %w", name, err)
+	}
+
+	err = wait.PollUntilContextCancel(ctx, jobPollInterval, true, func(ctx context.Context) (bool, error) {
+		_, err := kc.KubeClient.BatchV1().Jobs(kc.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete the previous run of job '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// waitForJobPod waits for the pod of the job to start, and returns its name. A pod which stays unschedulable for
+// jobUnschedulableTimeout, Eg:- as its PVC is never bound, fails the wait.
+func (kc *OpenshiftClient) waitForJobPod(ctx context.Context, name string) (string, error) {
+	var podName string
+	var unschedulableSince time.Time
+	err := wait.PollUntilContextCancel(ctx, jobPollInterval, true, func(ctx context.Context) (bool, error) {
+		pods, err := kc.KubeClient.CoreV1().Pods(kc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: jobNameLabel + "=" + name})
+		if err != nil || len(pods.Items) == 0 {
+			return false, err
+		}
+
+		pod := pods.Items[0]
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && slices.Contains(jobPodFailureReasons, cs.State.Waiting.Reason) {
+				return false, fmt.Errorf("%s: %s", cs.State.Waiting.Reason, cs.State.Waiting.Message)
+			}
+		}
+
+		if cond := unschedulable(&pod); cond == nil {
+			unschedulableSince = time.Time{}
+		} else if unschedulableSince.IsZero() {
+			unschedulableSince = time.Now()
+		} else if time.Since(unschedulableSince) > jobUnschedulableTimeout {
+			return false, fmt.Errorf("pod '%s' is unschedulable: %s: %s", pod.Name, cond.Reason, cond.Message)
+		}
+		podName = pod.Name
+
+		return pod.Status.Phase != corev1.PodPending, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("pod of job '%s' failed to start: %w", name, err)
+	}
+
+	return podName, nil
+}
+
+// unschedulable returns the PodScheduled condition of the pod if the pod cannot be scheduled, nil otherwise.
+func unschedulable(pod *corev1.Pod) *corev1.PodCondition {
+	for i, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			return &pod.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// waitForJob waits for the job to succeed or fail.
+func (kc *OpenshiftClient) waitForJob(ctx context.Context, name string) error {
+	var failed bool
+	err := wait.PollUntilContextCancel(ctx, jobPollInterval, true, func(ctx context.Context) (bool, error) {
+		job, err := kc.KubeClient.BatchV1().Jobs(kc.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		failed = job.Status.Failed > 0
+
+		return job.Status.Succeeded > 0 || failed, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for job '%s': %w", name, err)
+	}
+
+	if failed {
+		return fmt.Errorf("job '%s' failed", name)
+	}
+
+	return nil
+}
+
+// toJob returns the Kubernetes Job of the job, running as an unprivileged user as the restricted SCC requires.
+func toJob(job types.Job) *batchv1.Job {
+	backoffLimit := int32(0)
+	allowPrivilegeEscalation, runAsNonRoot := false, true
+	container := corev1.Container{
+		Name:    "job",
+		Image:   job.Image,
+		Command: job.Command,
+		TTY:     job.TTY,
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			RunAsNonRoot:             &runAsNonRoot,
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}
+	for _, key := range slices.Sorted(maps.Keys(job.Env)) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: key, Value: job.Env[key]})
+	}
+
+	var volumes []corev1.Volume
+	for _, claim := range slices.Sorted(maps.Keys(job.Volumes)) {
+		volumes = append(volumes, corev1.Volume{
+			Name:         claim,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: claim, MountPath: job.Volumes[claim]})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: job.Name, Labels: job.Labels},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: job.Labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{container},
+					Volumes:       volumes,
+				},
+			},
+		},
+	}
+}
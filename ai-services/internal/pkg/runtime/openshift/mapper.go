@@ -2,11 +2,13 @@ package openshift
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 func toOpenshiftPodList(pods *corev1.PodList) []types.Pod {
@@ -111,3 +113,14 @@ func toOpenShiftRouteList(routes []routev1.Route) []types.Route {
 
 	return routeList
 }
+
+func toOpenshiftPodEvent(eventType watch.EventType, pod *corev1.Pod) types.Event {
+	return types.Event{
+		Type:   "pod",
+		Action: strings.ToLower(string(eventType)),
+		ID:     string(pod.UID),
+		Name:   pod.Name,
+		PodID:  string(pod.UID),
+		Time:   time.Now(),
+	}
+}
@@ -167,7 +167,7 @@ func (kc *OpenshiftClient) ListImages() ([]types.Image, error) {
 }
 
 // PullImage pulls a container image.
-func (kc *OpenshiftClient) PullImage(image string) error {
+func (kc *OpenshiftClient) PullImage(image string, _ *types.PullOptions) error {
 	logger.Warningln("PullImage is not implemented for OpenshiftClient as image pulling is managed by kubelet.")
 
 	return nil
@@ -286,6 +286,58 @@ func (kc *OpenshiftClient) InspectContainer(nameOrID string) (*types.Container,
 	return nil, fmt.Errorf("cannot find container: %s", nameOrID)
 }
 
+// Events watches the pods matching the label filters and streams their changes, until the context is cancelled.
+func (kc *OpenshiftClient) Events(ctx context.Context, filters map[string][]string) (<-chan types.Event, error) {
+	selectors := []string{}
+	for _, lf := range filters["label"] {
+		if parts := strings.SplitN(lf, "=", labelPartsCount); len(parts) == labelPartsCount {
+			selectors = append(selectors, lf)
+		}
+	}
+
+	watcher, err := kc.KubeClient.CoreV1().Pods(kc.Namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector: strings.Join(selectors, ","),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch pods: %w", err)
+	}
+
+	out := make(chan types.Event)
+	go func() {
+		defer close(out)
+		defer watcher.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+				pod, isPod := e.Object.(*corev1.Pod)
+				if !isPod {
+					continue
+				}
+				select {
+				case out <- toOpenshiftPodEvent(e.Type, pod):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// ExecContainer runs a command inside a container.
+func (kc *OpenshiftClient) ExecContainer(nameOrID string, cmd []string) (int, error) {
+	logger.Warningln("Not implemented")
+
+	return -1, fmt.Errorf("exec is not supported for openshift runtime")
+}
+
 // ContainerExists checks if a container exists.
 func (kc *OpenshiftClient) ContainerExists(nameOrID string) (bool, error) {
 	// In Openshift, we check if any pod contains this container
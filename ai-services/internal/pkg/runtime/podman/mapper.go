@@ -1,6 +1,9 @@
 package podman
 
 import (
+	"strings"
+	"time"
+
 	"github.com/containers/podman/v5/libpod/define"
 	podmanTypes "github.com/containers/podman/v5/pkg/domain/entities/types"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
@@ -129,6 +132,7 @@ func toInspectContainer(input *define.InspectContainerData) *types.Container {
 		ID:     input.ID,
 		Name:   input.Name,
 		Status: input.State.Status,
+		Image:  input.ImageName,
 	}
 
 	// Set health status if available
@@ -141,10 +145,74 @@ func toInspectContainer(input *define.InspectContainerData) *types.Container {
 		container.Annotations = input.Config.Annotations
 	}
 
+	// Set env if available
+	if input.Config != nil && input.Config.Env != nil {
+		container.Env = toEnvMap(input.Config.Env)
+	}
+
 	// Set healthcheck start period if available
 	if input.Config != nil && input.Config.Healthcheck != nil {
 		container.HealthcheckStartPeriod = input.Config.Healthcheck.StartPeriod
+		// a test of ["NONE"] disables the healthcheck inherited from the image
+		container.HasHealthcheck = len(input.Config.Healthcheck.Test) > 0 && input.Config.Healthcheck.Test[0] != "NONE"
+	}
+
+	// Set resource limits if available
+	if input.HostConfig != nil {
+		container.MemoryLimit = input.HostConfig.Memory
+		container.NanoCPUs = toNanoCPUs(input.HostConfig)
+	}
+
+	// Set IP address if available, containers of a pod share the IP of the infra container
+	if input.NetworkSettings != nil {
+		container.IPAddress = toContainerIPAddress(input.NetworkSettings)
 	}
 
 	return container
 }
+
+func toEnvMap(env []string) map[string]string {
+	out := make(map[string]string, len(env))
+	for _, e := range env {
+		key, val, _ := strings.Cut(e, "=")
+		out[key] = val
+	}
+
+	return out
+}
+
+// toNanoCPUs returns the CPU limit of the container, which kube play sets as a CPU quota over a period.
+func toNanoCPUs(hostConfig *define.InspectContainerHostConfig) int64 {
+	if hostConfig.CpuQuota > 0 && hostConfig.CpuPeriod > 0 {
+		// the quota is a share of the period, a billion nano CPUs being one CPU
+		return hostConfig.CpuQuota * int64(time.Second) / int64(hostConfig.CpuPeriod)
+	}
+
+	return hostConfig.NanoCpus
+}
+
+func toContainerIPAddress(settings *define.InspectNetworkSettings) string {
+	if settings.IPAddress != "" {
+		return settings.IPAddress
+	}
+
+	for _, network := range settings.Networks {
+		if network != nil && network.IPAddress != "" {
+			return network.IPAddress
+		}
+	}
+
+	return ""
+}
+
+func toEvent(input podmanTypes.Event) types.Event {
+	return types.Event{
+		Type:         string(input.Type),
+		Action:       string(input.Action),
+		ID:           input.Actor.ID,
+		Name:         input.Actor.Attributes["name"],
+		PodID:        input.Actor.Attributes["podId"],
+		HealthStatus: input.HealthStatus,
+		Time:         time.Unix(0, input.TimeNano),
+	}
+}
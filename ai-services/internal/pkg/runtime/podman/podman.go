@@ -10,12 +10,17 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/containers/podman/v5/pkg/api/handlers"
 	"github.com/containers/podman/v5/pkg/bindings"
 	"github.com/containers/podman/v5/pkg/bindings/containers"
 	"github.com/containers/podman/v5/pkg/bindings/images"
 	"github.com/containers/podman/v5/pkg/bindings/kube"
 	"github.com/containers/podman/v5/pkg/bindings/pods"
+	"github.com/containers/podman/v5/pkg/bindings/system"
+	podmanTypes "github.com/containers/podman/v5/pkg/domain/entities/types"
 	"github.com/containers/podman/v5/pkg/specgen"
+	"github.com/distribution/reference"
+	dockerContainer "github.com/docker/docker/api/types/container"
 	"github.com/project-ai-services/ai-services/internal/pkg/constants"
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
@@ -23,7 +28,8 @@ import (
 )
 
 const (
-	logChannelBufferSize = 50
+	logChannelBufferSize   = 50
+	eventChannelBufferSize = 50
 )
 
 type PodmanClient struct {
@@ -61,13 +67,102 @@ func (pc *PodmanClient) ListImages() ([]types.Image, error) {
 	return toImageList(images), nil
 }
 
-func (pc *PodmanClient) PullImage(image string) error {
-	logger.Infof("Pulling image %s...\n", image)
-	_, err := images.Pull(pc.Context, image, nil)
+func (pc *PodmanClient) PullImage(image string, opts *types.PullOptions) error {
+	var err error
+	if opts != nil && opts.Progress != nil {
+		err = pc.pullWithProgress(image, opts)
+	} else {
+		logger.Infof("Pulling image %s...\n", image)
+		_, err = images.Pull(pc.Context, image, toPullOptions(opts))
+	}
+
+	if isAuthError(err) {
+		return fmt.Errorf("failed to pull image %s: %w: %w", image, types.ErrUnauthorized, err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to pull image %s: %w", image, err)
 	}
-	logger.Infof("Successfully pulled image %s\n", image)
+
+	if opts == nil || opts.Progress == nil {
+		logger.Infof("Successfully pulled image %s\n", image)
+	}
+
+	return nil
+}
+
+func toPullOptions(opts *types.PullOptions) *images.PullOptions {
+	pullOpts := &images.PullOptions{}
+	if opts == nil {
+		return pullOpts
+	}
+
+	if opts.AuthFile != "" {
+		pullOpts.WithAuthfile(opts.AuthFile)
+	}
+	if opts.Username != "" {
+		pullOpts.WithUsername(opts.Username).WithPassword(opts.Password)
+	}
+	if opts.TLSVerify != nil {
+		pullOpts.WithSkipTLSVerify(!*opts.TLSVerify)
+	}
+	if opts.Retries != nil {
+		pullOpts.WithRetry(*opts.Retries)
+	}
+
+	return pullOpts
+}
+
+// isAuthError reports whether the pull failed on the credentials, the registries report it in the message only.
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"unauthorized", "authentication required", "invalid username/password", "access to the resource is denied"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SaveImage writes the image to w as an OCI archive.
+func (pc *PodmanClient) SaveImage(image string, w io.Writer) error {
+	format := "oci-archive"
+	if err := images.Export(pc.Context, []string{image}, w, &images.ExportOptions{Format: &format}); err != nil {
+		return fmt.Errorf("failed to save image %s: %w", image, err)
+	}
+
+	return nil
+}
+
+// LoadImage loads the image of the archive read from r, and returns its names.
+func (pc *PodmanClient) LoadImage(r io.Reader) ([]string, error) {
+	report, err := images.Load(pc.Context, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image: %w", err)
+	}
+
+	return report.Names, nil
+}
+
+// TagImage adds the reference, which must hold a tag, to the image.
+func (pc *PodmanClient) TagImage(nameOrID, ref string) error {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return fmt.Errorf("invalid image reference %s: %w", ref, err)
+	}
+
+	tagged, ok := reference.TagNameOnly(named).(reference.Tagged)
+	if !ok {
+		return fmt.Errorf("image reference %s has no tag", ref)
+	}
+
+	if err := images.Tag(pc.Context, nameOrID, tagged.Tag(), named.Name(), nil); err != nil {
+		return fmt.Errorf("failed to tag image %s as %s: %w", nameOrID, ref, err)
+	}
 
 	return nil
 }
@@ -294,6 +389,42 @@ func (pc *PodmanClient) ContainerExists(nameOrID string) (bool, error) {
 	return containers.Exists(pc.Context, nameOrID, nil)
 }
 
+// ExecContainer runs the command inside the container, discarding its output, and returns its exit code.
+func (pc *PodmanClient) ExecContainer(nameOrID string, cmd []string) (int, error) {
+	sessionID, err := containers.ExecCreate(pc.Context, nameOrID, &handlers.ExecCreateConfig{
+		ExecOptions: dockerContainer.ExecOptions{
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          cmd,
+		},
+	})
+	if err != nil {
+		return -1, fmt.Errorf("failed to create exec session: %w", err)
+	}
+
+	defer func() {
+		if err := containers.ExecRemove(pc.Context, sessionID, nil); err != nil {
+			logger.Infof("failed to remove exec session %s: %v\n", sessionID, err, logger.VerbosityLevelDebug)
+		}
+	}()
+
+	attachOpts := new(containers.ExecStartAndAttachOptions).
+		WithOutputStream(io.Discard).
+		WithErrorStream(io.Discard).
+		WithAttachOutput(true).
+		WithAttachError(true)
+	if err := containers.ExecStartAndAttach(pc.Context, sessionID, attachOpts); err != nil {
+		return -1, fmt.Errorf("failed to run exec session: %w", err)
+	}
+
+	inspect, err := containers.ExecInspect(pc.Context, sessionID, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed to inspect exec session: %w", err)
+	}
+
+	return inspect.ExitCode, nil
+}
+
 // RunContainerWithSpec creates, starts, waits for, and removes a container with the given spec.
 // Returns the exit code of the container.
 func (pc *PodmanClient) RunContainerWithSpec(s *specgen.SpecGenerator) (int32, error) {
@@ -319,6 +450,80 @@ func (pc *PodmanClient) RunContainerWithSpec(s *specgen.SpecGenerator) (int32, e
 	return exitCode, nil
 }
 
+// RunContainerWithOutput creates, starts and waits for a container with the given spec, like RunContainerWithSpec,
+// with the output of the container written to out. Returns the exit code of the container.
+func (pc *PodmanClient) RunContainerWithOutput(s *specgen.SpecGenerator, out io.Writer) (int32, error) {
+	createResponse, err := containers.CreateWithSpec(pc.Context, s, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed to create container: %w", err)
+	}
+
+	containerID := createResponse.ID
+
+	// attach before starting the container, so that none of its output is missed
+	attachErr := make(chan error, 1)
+	attachReady := make(chan bool)
+	go func() {
+		attachErr <- containers.Attach(pc.Context, containerID, nil, out, out, attachReady, new(containers.AttachOptions).WithStream(true))
+	}()
+
+	select {
+	case <-attachReady:
+	case err := <-attachErr:
+		return -1, fmt.Errorf("failed to attach to container: %w", err)
+	}
+
+	if err := containers.Start(pc.Context, containerID, nil); err != nil {
+		return -1, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	exitCode, err := containers.Wait(pc.Context, containerID, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed to wait for container: %w", err)
+	}
+
+	// the attach returns once the output of the container is drained
+	if err := <-attachErr; err != nil {
+		logger.Infof("failed to read the output of container %s: %v\n", containerID, err, logger.VerbosityLevelDebug)
+	}
+
+	return exitCode, nil
+}
+
+// Events streams the podman events matching the filters (Eg:- container=<id>, event=health_status),
+// until the context is cancelled.
+func (pc *PodmanClient) Events(ctx context.Context, filters map[string][]string) (<-chan types.Event, error) {
+	eventChan := make(chan podmanTypes.Event, eventChannelBufferSize)
+	cancelChan := make(chan bool)
+
+	opts := new(system.EventsOptions).WithFilters(filters).WithStream(true)
+	if err := system.Events(pc.Context, eventChan, cancelChan, opts); err != nil {
+		close(cancelChan)
+
+		return nil, fmt.Errorf("failed to stream events: %w", err)
+	}
+
+	// closing the cancel channel closes the stream, which in turn closes the event channel
+	go func() {
+		<-ctx.Done()
+		close(cancelChan)
+	}()
+
+	out := make(chan types.Event, eventChannelBufferSize)
+	go func() {
+		defer close(out)
+		for e := range eventChan {
+			select {
+			case out <- toEvent(e):
+			case <-ctx.Done():
+				// keep draining until the stream is closed
+			}
+		}
+	}()
+
+	return out, nil
+}
+
 func (pc *PodmanClient) ListRoutes() ([]types.Route, error) {
 	logger.Errorf("unsupported method called!")
 
@@ -331,6 +536,18 @@ func (pc *PodmanClient) DeletePVCs(appLabel string) error {
 	return fmt.Errorf("unsupported method")
 }
 
+func (pc *PodmanClient) EnsurePVC(pvc types.PVC) error {
+	logger.Errorf("unsupported method called!")
+
+	return fmt.Errorf("unsupported method")
+}
+
+func (pc *PodmanClient) RunJob(ctx context.Context, job types.Job, output io.Writer) error {
+	logger.Errorf("unsupported method called!")
+
+	return fmt.Errorf("unsupported method")
+}
+
 // Type returns the runtime type for PodmanClient.
 func (pc *PodmanClient) Type() types.RuntimeType {
 	return types.RuntimeTypePodman
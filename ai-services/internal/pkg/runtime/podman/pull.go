@@ -0,0 +1,121 @@
+package podman
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/containers/podman/v5/pkg/auth"
+	"github.com/containers/podman/v5/pkg/bindings"
+	imageTypes "go.podman.io/image/v5/types"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime/types"
+)
+
+const (
+	pullStatusDownloading = "Downloading"
+	pullStatusComplete    = "Download complete"
+	pullStatusExists      = "Already exists"
+)
+
+// pullMessage is a message of the pull stream in compat mode, the progress of a layer or the error of the pull.
+type pullMessage struct {
+	Status         string `json:"status"`
+	ID             string `json:"id"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error string `json:"error"`
+}
+
+// layerProgress is the bytes pulled of a layer and its size.
+type layerProgress struct {
+	current, total int64
+}
+
+// pullWithProgress pulls the image in compat mode, where podman streams the bytes pulled of each layer, unlike the
+// libpod stream which only reports the layers copied. The bindings do not expose the compat mode, hence the request.
+func (pc *PodmanClient) pullWithProgress(image string, opts *types.PullOptions) error {
+	conn, err := bindings.GetClient(pc.Context)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("reference", image)
+	params.Set("compatMode", "true")
+	if opts.TLSVerify != nil {
+		params.Set("tlsVerify", strconv.FormatBool(*opts.TLSVerify))
+	}
+	if opts.Retries != nil {
+		params.Set("retry", strconv.FormatUint(uint64(*opts.Retries), 10))
+	}
+
+	header, err := auth.MakeXRegistryAuthHeader(&imageTypes.SystemContext{AuthFilePath: opts.AuthFile}, opts.Username, opts.Password)
+	if err != nil {
+		return err
+	}
+
+	response, err := conn.DoRequest(pc.Context, nil, http.MethodPost, "/images/pull", params, header)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if !response.IsSuccess() {
+		return response.Process(nil)
+	}
+
+	return decodePullStream(response.Body, opts.Progress)
+}
+
+// decodePullStream reports the progress of the stream, summed over the layers, and returns the error of the pull.
+// The podman releases without compat mode stream the libpod messages instead, the error is reported all the same.
+func decodePullStream(r io.Reader, progress func(current, total int64)) error {
+	layers := map[string]*layerProgress{}
+
+	dec := json.NewDecoder(r)
+	for {
+		var msg pullMessage
+		if err := dec.Decode(&msg); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode the pull stream: %w", err)
+		}
+
+		if msg.Error != "" {
+			return errors.New(msg.Error)
+		}
+
+		if msg.ID == "" {
+			continue
+		}
+
+		layer, ok := layers[msg.ID]
+		if !ok {
+			layer = &layerProgress{}
+			layers[msg.ID] = layer
+		}
+
+		switch msg.Status {
+		case pullStatusDownloading:
+			layer.current, layer.total = msg.ProgressDetail.Current, msg.ProgressDetail.Total
+		case pullStatusComplete, pullStatusExists:
+			layer.current = layer.total
+		default:
+			continue
+		}
+
+		var current, total int64
+		for _, l := range layers {
+			current += l.current
+			total += l.total
+		}
+		progress(current, total)
+	}
+}
@@ -1,6 +1,12 @@
 package types
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnauthorized is returned when the registry rejects the credentials of a pull, or requires credentials.
+var ErrUnauthorized = errors.New("registry authentication failed")
 
 // RuntimeType represents the type of container runtime.
 type RuntimeType string
@@ -42,8 +48,69 @@ type Container struct {
 	Name                   string
 	Status                 string
 	Health                 string
+	Image                  string
 	Annotations            map[string]string
+	Env                    map[string]string
+	HasHealthcheck         bool
 	HealthcheckStartPeriod time.Duration
+	IPAddress              string
+	// MemoryLimit is the memory limit in bytes, 0 if unlimited
+	MemoryLimit int64
+	// NanoCPUs is the CPU limit in billionths of a CPU, 0 if unlimited
+	NanoCPUs int64
+}
+
+// Event is a change reported by the runtime for a pod or a container.
+type Event struct {
+	// Type is the kind of object the event is about (Eg:- container, pod)
+	Type string
+	// Action is what happened to the object (Eg:- create, health_status)
+	Action string
+	ID     string
+	Name   string
+	// PodID is the ID of the pod the object belongs to, if any
+	PodID        string
+	HealthStatus string
+	Time         time.Time
+}
+
+// PullOptions are the options of an image pull, the zero value pulls with the defaults of the runtime.
+type PullOptions struct {
+	// AuthFile is the path of the registry auth file, in the format of the auth.json of podman login
+	AuthFile string
+	// Username and Password authenticate against the registry, instead of the auth file
+	Username string
+	Password string
+	// TLSVerify disables the verification of the certificate of the registry when set to false, nil keeps the default
+	TLSVerify *bool
+	// Retries is the number of times the runtime retries a failed pull, nil keeps the default
+	Retries *uint
+	// Progress is called with the bytes of the layers pulled so far and their total size, as the pull proceeds.
+	// The runtime does not log the pull when it is set, the caller reports its progress.
+	Progress func(current, total int64)
+}
+
+// PVC is a persistent volume claim created by the CLI.
+type PVC struct {
+	Name string
+	// Size is the storage requested (Eg:- 100Gi)
+	Size string
+	// StorageClass is the storage class of the claim, the default storage class of the cluster when empty
+	StorageClass string
+	Labels       map[string]string
+}
+
+// Job is a batch job run to completion by the runtime, in a single pod.
+type Job struct {
+	Name    string
+	Image   string
+	Command []string
+	Env     map[string]string
+	Labels  map[string]string
+	// Volumes maps the names of the persistent volume claims mounted in the pod to their mount paths
+	Volumes map[string]string
+	// TTY allocates a terminal to the container, for the tools drawing their progress on a terminal only
+	TTY bool
 }
 
 type Image struct {
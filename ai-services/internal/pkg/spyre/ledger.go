@@ -0,0 +1,409 @@
+// Package spyre keeps a ledger of the Spyre cards allocated to application containers, shared by all
+// the ai-services processes on the host, so that concurrent deployments never allocate the same card.
+package spyre
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/project-ai-services/ai-services/internal/pkg/cli/helpers"
+	"github.com/project-ai-services/ai-services/internal/pkg/constants"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+	"github.com/project-ai-services/ai-services/internal/pkg/runtime"
+	"github.com/project-ai-services/ai-services/internal/pkg/vars"
+)
+
+const (
+	ledgerFileName  = "allocations.json"
+	lockFileName    = "allocations.lock"
+	dirPermissions  = 0o755
+	filePermissions = 0o644
+	vfioDevicesPath = "/dev/vfio"
+	// placeholderCard is the PCI address of the simulated cards when the host lacks Spyre cards
+	placeholderCard = "SPYRE-CARD-%d"
+)
+
+// Allocation records a Spyre card allocated to an application.
+type Allocation struct {
+	PCIAddress  string `json:"pciAddress"`
+	Application string `json:"application"`
+	// Pod and Container are empty while the card is only reserved by a running create
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	// PID is the process holding the reservation, until the card is assigned to a container
+	PID         int       `json:"pid,omitempty"`
+	AllocatedAt time.Time `json:"allocatedAt"`
+}
+
+// Reserved reports whether the card is reserved, but not yet assigned to a container.
+func (a Allocation) Reserved() bool {
+	return a.Pod == ""
+}
+
+// Ledger holds all the Spyre card allocations on the host.
+type Ledger struct {
+	Allocations []Allocation `json:"allocations"`
+}
+
+// find returns the index of the allocation for the card, -1 if the card is not allocated.
+func (l *Ledger) find(pciAddress string) int {
+	return slices.IndexFunc(l.Allocations, func(a Allocation) bool {
+		return a.PCIAddress == pciAddress
+	})
+}
+
+// Reserve reconciles the ledger and reserves the Spyre cards requested by the containers of the application,
+// out of the cards which are neither in use nor allocated to any other pod, even a stopped one.
+// The cards of each container are placed according to the topology of the host, see Plan.
+func Reserve(rt runtime.Runtime, appName string, requests []Request) (Placement, error) {
+	var placement Placement
+
+	err := update(func(l *Ledger) error {
+		if err := reconcile(rt, l); err != nil {
+			return err
+		}
+
+		freeCards, err := helpers.FindFreeSpyreCards()
+		if err != nil {
+			return fmt.Errorf("failed to find free Spyre Cards: %w", err)
+		}
+
+		var unallocated []string
+		for _, card := range freeCards {
+			card = strings.TrimSpace(card)
+			if l.find(card) != -1 {
+				logger.Infof("Spyre card %s is allocated in the ledger, skipping\n", card, logger.VerbosityLevelDebug)
+
+				continue
+			}
+			unallocated = append(unallocated, card)
+		}
+
+		placement, err = Plan(unallocated, requests, readTopology())
+		if err != nil {
+			return err
+		}
+
+		for _, card := range placement.Cards() {
+			l.Allocations = append(l.Allocations, Allocation{
+				PCIAddress:  card,
+				Application: appName,
+				PID:         os.Getpid(),
+				AllocatedAt: time.Now(),
+			})
+		}
+
+		return nil
+	})
+
+	return placement, err
+}
+
+// Simulate places the Spyre cards of the requests without reserving them, for a dry run. Cards allocated
+// in the ledger are left out. When the host does not have enough free Spyre cards, placeholder addresses
+// are used instead, so that the rendered specs can still be reviewed.
+func Simulate(requests []Request) (Placement, error) {
+	if _, err := os.Stat(vfioDevicesPath); err == nil {
+		placement, err := simulateOnHost(requests)
+		if err == nil {
+			return placement, nil
+		}
+		logger.Warningf("Cannot allocate the Spyre cards on this host (%v), using placeholder cards\n", err)
+	}
+
+	var cards []string
+	for _, req := range requests {
+		if len(req.Pinned) > 0 {
+			cards = append(cards, req.Pinned...)
+
+			continue
+		}
+
+		for range req.Count {
+			cards = append(cards, fmt.Sprintf(placeholderCard, len(cards)))
+		}
+	}
+
+	return Plan(cards, requests, nil)
+}
+
+func simulateOnHost(requests []Request) (Placement, error) {
+	freeCards, err := helpers.FindFreeSpyreCards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find free Spyre Cards: %w", err)
+	}
+
+	// the ledger is only read, it is always replaced atomically
+	l, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	var unallocated []string
+	for _, card := range freeCards {
+		if card = strings.TrimSpace(card); l.find(card) == -1 {
+			unallocated = append(unallocated, card)
+		}
+	}
+
+	return Plan(unallocated, requests, readTopology())
+}
+
+// Assign records the reserved cards as allocated to the containers of the pod.
+// containerCards maps a container name to the PCI addresses of the cards assigned to it.
+func Assign(appName, podName string, containerCards map[string][]string) error {
+	if len(containerCards) == 0 {
+		return nil
+	}
+
+	return update(func(l *Ledger) error {
+		return assign(l, appName, podName, containerCards)
+	})
+}
+
+// Reassign replaces the cards allocated to the pod of the application with the given ones, when the pod
+// is deployed again. The cards it no longer uses are released, see Assign for containerCards.
+func Reassign(appName, podName string, containerCards map[string][]string) error {
+	return update(func(l *Ledger) error {
+		l.Allocations = slices.DeleteFunc(l.Allocations, func(a Allocation) bool {
+			return a.Application == appName && a.Pod == podName
+		})
+
+		return assign(l, appName, podName, containerCards)
+	})
+}
+
+func assign(l *Ledger, appName, podName string, containerCards map[string][]string) error {
+	for container, cards := range containerCards {
+		for _, card := range cards {
+			allocation := Allocation{
+				PCIAddress:  card,
+				Application: appName,
+				Pod:         podName,
+				Container:   container,
+				AllocatedAt: time.Now(),
+			}
+
+			i := l.find(card)
+			if i == -1 {
+				l.Allocations = append(l.Allocations, allocation)
+
+				continue
+			}
+
+			if l.Allocations[i].Application != appName {
+				return fmt.Errorf("spyre card %s is allocated to application '%s'", card, l.Allocations[i].Application)
+			}
+			l.Allocations[i] = allocation
+		}
+	}
+
+	return nil
+}
+
+// ReleasePods releases the cards allocated to the given pods of the application.
+func ReleasePods(appName string, podNames []string) error {
+	return release(func(a Allocation) bool {
+		return a.Application == appName && slices.Contains(podNames, a.Pod)
+	})
+}
+
+// ReleaseReservations releases the cards reserved by this process for the application,
+// which have not been assigned to any container.
+func ReleaseReservations(appName string) error {
+	return release(func(a Allocation) bool {
+		return a.Application == appName && a.Reserved() && a.PID == os.Getpid()
+	})
+}
+
+// List reconciles the ledger and returns the allocations sorted by PCI address.
+func List(rt runtime.Runtime) ([]Allocation, error) {
+	var allocations []Allocation
+
+	err := update(func(l *Ledger) error {
+		if err := reconcile(rt, l); err != nil {
+			return err
+		}
+		allocations = slices.Clone(l.Allocations)
+
+		return nil
+	})
+
+	slices.SortFunc(allocations, func(a, b Allocation) int {
+		return strings.Compare(a.PCIAddress, b.PCIAddress)
+	})
+
+	return allocations, err
+}
+
+func release(matches func(Allocation) bool) error {
+	return update(func(l *Ledger) error {
+		l.Allocations = slices.DeleteFunc(l.Allocations, matches)
+
+		return nil
+	})
+}
+
+// reconcile aligns the ledger with the cards set in the AIU_PCIE_IDS env of the application containers:
+// cards used by containers missing from the ledger are added, allocations of pods which no longer exist
+// are dropped, and so are the reservations of processes which are no longer running.
+func reconcile(rt runtime.Runtime, l *Ledger) error {
+	inUse, existingPods, err := cardsInUse(rt)
+	if err != nil {
+		return err
+	}
+
+	l.Allocations = slices.DeleteFunc(l.Allocations, func(a Allocation) bool {
+		if a.Reserved() {
+			return !processRunning(a.PID)
+		}
+
+		return !existingPods[a.Pod]
+	})
+
+	for _, used := range inUse {
+		i := l.find(used.PCIAddress)
+		if i == -1 {
+			logger.Infof("Adding Spyre card %s used by '%s' to the ledger\n", used.PCIAddress, used.Pod, logger.VerbosityLevelDebug)
+			l.Allocations = append(l.Allocations, used)
+
+			continue
+		}
+
+		// the pod of a reserved card has been created, but not yet recorded by its create
+		if l.Allocations[i].Reserved() {
+			l.Allocations[i] = used
+
+			continue
+		}
+
+		if l.Allocations[i].Pod != used.Pod || l.Allocations[i].Container != used.Container {
+			logger.Warningf("Spyre card %s is recorded for pod '%s' but used by pod '%s', updating the ledger\n",
+				used.PCIAddress, l.Allocations[i].Pod, used.Pod)
+			l.Allocations[i] = used
+		}
+	}
+
+	return nil
+}
+
+// cardsInUse returns the cards set in the env of the application containers, whether running or not,
+// along with the names of all the existing application pods.
+func cardsInUse(rt runtime.Runtime) ([]Allocation, map[string]bool, error) {
+	pods, err := rt.ListPods(map[string][]string{
+		"label": {constants.ApplicationAnnotationKey},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var inUse []Allocation
+	existingPods := make(map[string]bool, len(pods))
+
+	for _, pod := range pods {
+		existingPods[pod.Name] = true
+
+		for _, container := range pod.Containers {
+			cInfo, err := rt.InspectContainer(container.ID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to inspect container %s: %w", container.Name, err)
+			}
+
+			for _, card := range strings.Fields(cInfo.Env[string(constants.PCIAddressKey)]) {
+				inUse = append(inUse, Allocation{
+					PCIAddress:  card,
+					Application: pod.Labels[constants.ApplicationAnnotationKey],
+					Pod:         pod.Name,
+					Container:   strings.TrimPrefix(cInfo.Name, pod.Name+"-"),
+					AllocatedAt: time.Now(),
+				})
+			}
+		}
+	}
+
+	return inUse, existingPods, nil
+}
+
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	// signal 0 only checks whether the process exists
+	err := syscall.Kill(pid, syscall.Signal(0))
+
+	return err == nil || err == syscall.EPERM
+}
+
+// update runs fn on the ledger while holding an exclusive lock on it, and saves the ledger if fn succeeds.
+func update(fn func(l *Ledger) error) error {
+	if err := os.MkdirAll(vars.SpyreLedgerDirectory, dirPermissions); err != nil {
+		return fmt.Errorf("failed to create Spyre ledger directory: %w", err)
+	}
+
+	lock, err := os.OpenFile(filepath.Join(vars.SpyreLedgerDirectory, lockFileName), os.O_CREATE|os.O_RDWR, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to open Spyre ledger lock: %w", err)
+	}
+	defer func() { _ = lock.Close() }()
+
+	// the lock is released when the file is closed, even if the process dies
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock Spyre ledger: %w", err)
+	}
+
+	l, err := load()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(l); err != nil {
+		return err
+	}
+
+	return save(l)
+}
+
+func load() (*Ledger, error) {
+	l := &Ledger{}
+
+	data, err := os.ReadFile(filepath.Join(vars.SpyreLedgerDirectory, ledgerFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+
+		return nil, fmt.Errorf("failed to read Spyre ledger: %w", err)
+	}
+
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("failed to parse Spyre ledger: %w", err)
+	}
+
+	return l, nil
+}
+
+func save(l *Ledger) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Spyre ledger: %w", err)
+	}
+
+	path := filepath.Join(vars.SpyreLedgerDirectory, ledgerFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePermissions); err != nil {
+		return fmt.Errorf("failed to write Spyre ledger: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write Spyre ledger: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package spyre
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	spyreconfig "github.com/project-ai-services/ai-services/internal/pkg/bootstrap/spyreconfig/spyre"
+	"github.com/project-ai-services/ai-services/internal/pkg/logger"
+)
+
+const noNUMANode = -1
+
+// Request is the number of Spyre cards required by a container of a pod.
+type Request struct {
+	Pod       string
+	Container string
+	Count     int
+	// Pinned are the PCI addresses of the cards explicitly requested for the container
+	Pinned []string
+}
+
+// Placement maps a pod name to the PCI addresses of the cards selected for each of its containers.
+type Placement map[string]map[string][]string
+
+// Cards returns the PCI addresses of all the cards of the placement, sorted.
+func (p Placement) Cards() []string {
+	var cards []string
+	for _, containers := range p {
+		for _, containerCards := range containers {
+			cards = append(cards, containerCards...)
+		}
+	}
+	slices.Sort(cards)
+
+	return cards
+}
+
+func (p Placement) set(pod, container string, cards []string) {
+	if p[pod] == nil {
+		p[pod] = map[string][]string{}
+	}
+	p[pod][container] = cards
+}
+
+// iommuGroup holds the free cards of an IOMMU group, which are always handed out together,
+// as VFIO assigns a whole group to a single container.
+type iommuGroup struct {
+	cards []string
+}
+
+// numaNode holds the free IOMMU groups of a NUMA node.
+type numaNode struct {
+	id     int
+	groups []iommuGroup
+	free   int
+}
+
+// Plan selects the cards of each request out of the free cards, using the topology of the cards.
+// Pinned cards are honoured as they are. Otherwise the cards of a container are taken from a single
+// NUMA node whenever possible, picking the node with the fewest free cards that still fits, so that the
+// larger nodes are kept for the next containers. Containers are only spread across NUMA nodes as a last resort.
+func Plan(free []string, requests []Request, topology map[string]spyreconfig.CardTopology) (Placement, error) {
+	required := 0
+	for _, req := range requests {
+		required += req.Count
+	}
+	if required > len(free) {
+		return nil, fmt.Errorf("insufficient spyre cards. Require: %d spyre cards to proceed, %d available", required, len(free))
+	}
+
+	placement := Placement{}
+	available := make(map[string]bool, len(free))
+	for _, card := range free {
+		available[card] = true
+	}
+
+	// pinned requests go first, so that the cards they pin are never handed out to another container
+	var unpinned []Request
+	for _, req := range requests {
+		if len(req.Pinned) == 0 {
+			unpinned = append(unpinned, req)
+
+			continue
+		}
+
+		if len(req.Pinned) != req.Count {
+			return nil, fmt.Errorf("container '%s' of pod '%s' pins %d spyre cards but requires %d",
+				req.Container, req.Pod, len(req.Pinned), req.Count)
+		}
+
+		for _, card := range req.Pinned {
+			if !available[card] {
+				return nil, fmt.Errorf("spyre card %s pinned for container '%s' of pod '%s' is not free", card, req.Container, req.Pod)
+			}
+			delete(available, card)
+		}
+		placement.set(req.Pod, req.Container, req.Pinned)
+	}
+
+	// the largest requests are the hardest to fit within a single NUMA node, so they are placed first
+	slices.SortStableFunc(unpinned, func(a, b Request) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	for _, req := range unpinned {
+		if req.Count == 0 {
+			continue
+		}
+
+		nodes := groupByNUMANode(available, topology)
+
+		cards, spread := selectCards(nodes, req.Count)
+		if len(cards) < req.Count {
+			return nil, fmt.Errorf("cannot place %d spyre cards for container '%s' of pod '%s' without splitting an IOMMU group",
+				req.Count, req.Container, req.Pod)
+		}
+
+		if spread {
+			logger.Warningf("Spyre cards of container '%s' of pod '%s' span multiple NUMA nodes: %v\n", req.Container, req.Pod, cards)
+		}
+
+		for _, card := range cards {
+			delete(available, card)
+		}
+		placement.set(req.Pod, req.Container, cards)
+	}
+
+	return placement, nil
+}
+
+// selectCards picks count cards out of the nodes, and reports whether they span multiple NUMA nodes.
+func selectCards(nodes []numaNode, count int) ([]string, bool) {
+	// best fit: the node with the fewest free cards which can provide all of them
+	var best *numaNode
+	var bestCards []string
+	for i := range nodes {
+		cards := takeGroups(nodes[i].groups, count)
+		if len(cards) == count && (best == nil || nodes[i].free < best.free) {
+			best, bestCards = &nodes[i], cards
+		}
+	}
+
+	if best != nil {
+		return bestCards, false
+	}
+
+	// spread across as few nodes as possible, starting with the node with the most free cards
+	byFree := slices.Clone(nodes)
+	slices.SortStableFunc(byFree, func(a, b numaNode) int {
+		return cmp.Compare(b.free, a.free)
+	})
+
+	var groups []iommuGroup
+	for _, node := range byFree {
+		groups = append(groups, node.groups...)
+	}
+
+	return takeGroups(groups, count), true
+}
+
+// takeGroups takes whole IOMMU groups, in order, until count cards are taken or no group fits anymore.
+func takeGroups(groups []iommuGroup, count int) []string {
+	var cards []string
+	for _, group := range groups {
+		if len(cards)+len(group.cards) <= count {
+			cards = append(cards, group.cards...)
+		}
+		if len(cards) == count {
+			break
+		}
+	}
+
+	return cards
+}
+
+// groupByNUMANode groups the available cards by NUMA node and IOMMU group, sorted by PCI address.
+// Cards missing from the topology are considered on no NUMA node, each in its own IOMMU group.
+func groupByNUMANode(available map[string]bool, topology map[string]spyreconfig.CardTopology) []numaNode {
+	nodeGroups := map[int]map[string][]string{}
+	for _, card := range slices.Sorted(maps.Keys(available)) {
+		node, group := noNUMANode, card
+		if t, ok := topology[card]; ok {
+			node = t.NUMANode
+			if t.IOMMUGroup != "" {
+				group = t.IOMMUGroup
+			}
+		}
+
+		if nodeGroups[node] == nil {
+			nodeGroups[node] = map[string][]string{}
+		}
+		nodeGroups[node][group] = append(nodeGroups[node][group], card)
+	}
+
+	nodes := make([]numaNode, 0, len(nodeGroups))
+	for _, id := range slices.Sorted(maps.Keys(nodeGroups)) {
+		node := numaNode{id: id}
+		for _, cards := range nodeGroups[id] {
+			node.groups = append(node.groups, iommuGroup{cards: cards})
+			node.free += len(cards)
+		}
+
+		// groups hold sorted cards, so ordering on the first card keeps neighbouring cards together
+		slices.SortFunc(node.groups, func(a, b iommuGroup) int {
+			return cmp.Compare(a.cards[0], b.cards[0])
+		})
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// readTopology returns the topology of the Spyre cards, or an empty topology if it cannot be read,
+// in which case the cards are placed in the order of their PCI addresses.
+func readTopology() map[string]spyreconfig.CardTopology {
+	topology, err := spyreconfig.GetSpyreTopology()
+	if err != nil {
+		logger.Infof("Failed to read the Spyre cards topology, ignoring it: %v\n", err, logger.VerbosityLevelDebug)
+
+		return map[string]spyreconfig.CardTopology{}
+	}
+
+	return topology
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const diffContextLines = 3
+
+// UnifiedDiff returns the unified diff between the two texts, empty if they are equal.
+func UnifiedDiff(fromName, toName, from, to string) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute diff: %w", err)
+	}
+
+	return diff, nil
+}
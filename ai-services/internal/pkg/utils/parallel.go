@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+)
+
+// ForEachParallel calls fn for each of the items, with at most limit calls running at once.
+// All the items are processed even if some of them fail, the errors are joined.
+func ForEachParallel[T any](items []T, limit int, fn func(item T) error) error {
+	limit = max(limit, 1)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	sem := make(chan struct{}, limit)
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(item); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
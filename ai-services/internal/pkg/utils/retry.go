@@ -1,18 +1,40 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/project-ai-services/ai-services/internal/pkg/logger"
 )
 
+const exponentialBackoffFactor = 2
+
 // BackoffFunc type definition.
 type BackoffFunc func(currentDelay time.Duration) time.Duration
 
+// PermanentError wraps an error which retrying cannot fix (Eg:- a rejected login), Retry returns it without retrying.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks the error as not retryable.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
 // Retry -> retries based on the retry attempts and initialDelay time set on failure.
 // Does exponentialBackOff based on the provided BackoffFunc.
 // Set backoff func to nil, if exponentialBackoff is not required.
+// The errors marked with Permanent are returned right away.
 func Retry(
 	attempts int,
 	initialDelay time.Duration,
@@ -28,6 +50,11 @@ func Retry(
 		return nil
 	}
 
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return permanent.Err
+	}
+
 	for i := range attempts {
 		logger.Infof("\n[Retry] Attempt %d/%d...\n", i+1, attempts, 0)
 
@@ -35,6 +62,10 @@ func Retry(
 			return nil
 		}
 
+		if errors.As(err, &permanent) {
+			return permanent.Err
+		}
+
 		// At Last attempt — stop
 		if i == attempts-1 {
 			break
@@ -52,3 +83,10 @@ func Retry(
 
 	return fmt.Errorf("retry failed after %d attempts with err: %w", attempts, err)
 }
+
+// ExponentialBackoff returns a BackoffFunc which doubles the delay on every call, up to maxDelay.
+func ExponentialBackoff(maxDelay time.Duration) BackoffFunc {
+	return func(currentDelay time.Duration) time.Duration {
+		return min(currentDelay*exponentialBackoffFactor, maxDelay)
+	}
+}
@@ -250,6 +250,33 @@ func SetNestedValue(out map[string]any, dottedKey string, value any) {
 	current[last] = value
 }
 
+// GetNestedValue fetches a nested value from a map based on a dotted key notation.
+// For example, for summarize.enabled it returns map["summarize"]["enabled"].
+// The second return value reports whether the full path exists.
+func GetNestedValue(in map[string]any, dottedKey string) (any, bool) {
+	parts := strings.Split(dottedKey, ".")
+	current := in
+
+	for i, key := range parts {
+		val, ok := current[key]
+		if !ok {
+			return nil, false
+		}
+
+		if i == len(parts)-1 {
+			return val, true
+		}
+
+		cast, ok := val.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current = cast
+	}
+
+	return nil, false
+}
+
 // rfc1035HostnameRegex validates RFC 1035 hostname format:
 // - 1-63 characters.
 // - lowercase letters, numbers, and hyphens only.
@@ -15,8 +15,18 @@ var (
 var (
 	// SpyreCardAnnotationRegex -> ai-services.io/<containerName>--spyre-cards.
 	SpyreCardAnnotationRegex = regexp.MustCompile(`^ai-services\.io\/([A-Za-z0-9][-A-Za-z0-9_.]*)--spyre-cards$`)
-	ToolImage                = "icr.io/ai-services/tools:0.7"
-	ModelDirectory           = "/var/lib/ai-services/models"
+	// SpyrePCIAddressesAnnotationRegex -> ai-services.io/<containerName>--spyre-pci-addresses, pins the Spyre cards of a container.
+	SpyrePCIAddressesAnnotationRegex = regexp.MustCompile(`^ai-services\.io\/([A-Za-z0-9][-A-Za-z0-9_.]*)--spyre-pci-addresses$`)
+	ToolImage                        = "icr.io/ai-services/tools:0.7"
+	ModelDirectory                   = "/var/lib/ai-services/models"
+	// RegistriesConfig holds the registry mirrors the image references of the templates are rewritten to.
+	RegistriesConfig = "/etc/ai-services/registries.yaml"
+	// ModelSourcesConfig holds the sources the models are downloaded from instead of the Hugging Face hub.
+	ModelSourcesConfig = "/etc/ai-services/models.yaml"
+	// ImageLockDirectory holds the lock files pinning the images of the templates to their digests.
+	ImageLockDirectory = "/var/lib/ai-services/locks"
+	// SpyreLedgerDirectory holds the ledger of the Spyre cards allocated to the containers of the applications.
+	SpyreLedgerDirectory = "/var/lib/ai-services/spyre"
 )
 
 type Label string
@@ -30,7 +40,14 @@ var (
 	LparAffinityThreshold = 70
 )
 
+var (
+	// CLIVersion is the version of the running CLI, recorded along with the deployed applications.
+	CLIVersion = "unknown"
+)
+
 var (
 	RetryCount    = 3
 	RetryInterval = 5 * time.Second
+	// MaxParallelDownloads is the number of images pulled, or models downloaded, at once.
+	MaxParallelDownloads = 3
 )